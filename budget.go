@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget limits the total volume of formatted log lines in bytes per second.
+// When the budget runs low lower levels are shed first, while Error and Fatal lines are never dropped.
+// A single Budget may be shared between multiple loggers to enforce a global limit.
+type Budget struct {
+	reporter
+	mu     sync.Mutex
+	rate   float64 // bytes per second
+	burst  float64
+	tokens float64
+	last   int64
+
+	shedEvents [numLevels]uint64
+	shedBytes  [numLevels]uint64
+}
+
+// shedding summarises the lines a Budget dropped for a single level.
+type shedding struct {
+	level  Level
+	events uint64
+	bytes  uint64
+}
+
+// NewBudget creates a budget that refills at bytesPerSecond up to burst bytes.
+// The volume that was shed is reported per level every interval.
+func NewBudget(bytesPerSecond, burst int, interval time.Duration) *Budget {
+	b := &Budget{
+		rate:   float64(bytesPerSecond),
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+	b.interval = int64(interval)
+	return b
+}
+
+// allow reports whether a line of n bytes at the given level fits in the budget.
+// Every level below Warning keeps a growing part of the burst in reserve for the levels above it.
+func (b *Budget) allow(lvl Level, n int, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now.UnixNano())
+	size := float64(n)
+	if lvl <= LevelError {
+		// never shed errors, but do let them consume the budget
+		b.tokens -= size
+		if b.tokens < -b.burst {
+			b.tokens = -b.burst
+		}
+		return true
+	}
+
+	reserve := b.burst * float64(lvl-LevelWarning) / float64(LevelTrace-LevelWarning+1)
+	if b.tokens-size < reserve {
+		if lvl <= LevelTrace {
+			b.shedEvents[lvl-1]++
+			b.shedBytes[lvl-1] += uint64(n)
+		}
+		return false
+	}
+	b.tokens -= size
+	return true
+}
+
+// refill adds the tokens that were earned since the last call.
+func (b *Budget) refill(now int64) {
+	if b.last == 0 {
+		b.last = now
+		return
+	}
+	if elapsed := now - b.last; elapsed > 0 {
+		b.tokens += b.rate * float64(elapsed) / float64(time.Second)
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// shed returns the volume shed per level since the previous report once the report interval elapsed.
+func (b *Budget) shed(now time.Time) []shedding {
+	if !b.due(now) {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var shed []shedding
+	for i := range b.shedEvents {
+		if b.shedEvents[i] > 0 {
+			shed = append(shed, shedding{level: Level(i + 1), events: b.shedEvents[i], bytes: b.shedBytes[i]})
+			b.shedEvents[i] = 0
+			b.shedBytes[i] = 0
+		}
+	}
+	return shed
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBudget(t *testing.T) {
+	budget := NewBudget(100, 100, time.Minute)
+	now := time.Unix(100, 0)
+
+	// info keeps a quarter of the burst in reserve
+	if !budget.allow(LevelInfo, 70, now) {
+		t.Errorf("expected info line within budget to be allowed")
+	}
+	if budget.allow(LevelInfo, 10, now) {
+		t.Errorf("expected info line to be shed when reaching the reserve")
+	}
+	if !budget.allow(LevelWarning, 30, now) {
+		t.Errorf("expected warning line to use the reserve")
+	}
+	if !budget.allow(LevelError, 1000, now) {
+		t.Errorf("expected error line to never be shed")
+	}
+
+	// budget is refilled over time
+	if !budget.allow(LevelTrace, 10, now.Add(2*time.Second)) {
+		t.Errorf("expected trace line to be allowed after refill")
+	}
+
+	shed := budget.shed(now)
+	if len(shed) != 1 || shed[0] != (shedding{level: LevelInfo, events: 1, bytes: 10}) {
+		t.Errorf("unexpected shed report: %v", shed)
+	}
+}
+
+func TestLoggerBudget(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Budget: NewBudget(1, 200, time.Hour)})
+
+	for i := 0; i < 10; i++ {
+		log.Info("filling up the budget")
+	}
+	log.Error("must not be dropped")
+
+	got := buf.String()
+	if strings.Count(got, "lvl=info") >= 10 {
+		t.Errorf("expected info lines to be shed, got: %s", got)
+	}
+	if !strings.Contains(got, "must not be dropped") {
+		t.Errorf("expected error line to be written, got: %s", got)
+	}
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// BufferOptions configures a buffered logger created by Logger.Buffered.
+type BufferOptions struct {
+	// Trigger is the level at which all held events are flushed, after which events are written directly.
+	// Defaults to LevelError.
+	Trigger Level
+
+	// SuccessLevel is the minimum level of the held events that are flushed when the buffer is closed
+	// without failure. Zero discards all held events.
+	SuccessLevel Level
+
+	// MaxBytes caps the approximate memory held by the buffer, the oldest events are dropped first.
+	// Zero means no limit.
+	MaxBytes int
+}
+
+// Buffer holds the events of a buffered logger in memory, known as a "fingers-crossed" logger.
+// Nothing is written unless an event at the trigger level is logged or the buffer is closed.
+type Buffer struct {
+	mu        sync.Mutex
+	opts      BufferOptions
+	parent    *Logger
+	events    []*Event
+	size      int
+	dropped   int
+	triggered bool
+	closed    bool
+}
+
+// Buffered returns a clone of the logger that captures events of every level in a Buffer.
+// Close the buffer once the unit of work, for example an HTTP request, is done.
+func (l *Logger) Buffered(opts BufferOptions) (*Logger, *Buffer) {
+	if opts.Trigger <= 0 {
+		opts.Trigger = LevelError
+	}
+
+	b := &Buffer{opts: opts, parent: l}
+	clone := l.clone()
+	clone.level = LevelTrace
+	clone.buffer = b
+	clone.recorder = nil // the buffer already holds every event, a recorder would write them twice
+	return clone, b
+}
+
+// add holds the event or writes it directly once the buffer was triggered.
+func (b *Buffer) add(e *Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.triggered || b.closed {
+		b.write(e)
+		return
+	}
+
+	b.events = append(b.events, e.Clone())
+	b.size += eventSize(e)
+	for b.opts.MaxBytes > 0 && b.size > b.opts.MaxBytes && len(b.events) > 1 {
+		// drop the oldest event
+		b.size -= eventSize(b.events[0])
+		b.events[0] = nil
+		b.events = b.events[1:]
+		b.dropped++
+	}
+
+	if e.Level > 0 && e.Level <= b.opts.Trigger {
+		b.triggered = true
+		b.flushLocked(LevelTrace)
+	}
+}
+
+// write writes the event if the parent logger would have written it, or when the buffer was triggered.
+func (b *Buffer) write(e *Event) {
+	if b.triggered || b.parent.should(e.Level) {
+		b.parent.write(e)
+	}
+}
+
+// Close flushes the held events and writes subsequent events directly. When failed is true all held events are
+// flushed, otherwise only the events at or above the SuccessLevel.
+func (b *Buffer) Close(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+
+	if failed {
+		b.triggered = true
+		b.flushLocked(LevelTrace)
+	} else {
+		b.flushLocked(b.opts.SuccessLevel)
+	}
+}
+
+// Triggered returns true when an event at the trigger level was logged.
+func (b *Buffer) Triggered() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.triggered
+}
+
+func (b *Buffer) flushLocked(lvl Level) {
+	if b.dropped > 0 && lvl > 0 {
+		e := b.parent.event(LevelWarning, fmt.Sprintf("dropped %d buffered events over memory limit", b.dropped))
+		b.parent.write(e)
+		putEvent(e)
+	}
+	for _, e := range b.events {
+		if e.Level <= lvl {
+			b.write(e)
+		}
+	}
+	b.events = nil
+	b.size = 0
+	b.dropped = 0
+}
+
+// eventSize approximates the memory used by a held event.
+func eventSize(e *Event) int {
+	size := len(e.Module) + len(e.Message) + len(e.Filename) + 64
+	for _, f := range e.Fields {
+		size += len(f.Key) + 16
+		if s, ok := f.Value.(string); ok {
+			size += len(s)
+		}
+	}
+	return size
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestBuffered(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter(), Level: LevelInfo})
+
+	buffered, b := log.Buffered(BufferOptions{})
+	buffered.Debug("loading mandate")
+	buffered.Info("collecting")
+	if buf.Len() != 0 {
+		t.Errorf("expected events to be held, got: %s", buf.String())
+	}
+
+	buffered.Error("collection failed")
+	buffered.Trace("after trigger")
+	b.Close(false)
+
+	want := "debug - loading mandate\n" +
+		"info - collecting\n" +
+		"error - collection failed\n" +
+		"trace - after trigger\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestBuffered_close(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter(), Level: LevelInfo})
+
+	buffered, b := log.Buffered(BufferOptions{SuccessLevel: LevelInfo})
+	buffered.Debug("loading mandate")
+	buffered.Info("collecting")
+	b.Close(false)
+
+	want := "info - collecting\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestBuffered_maxBytes(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter()})
+
+	buffered, b := log.Buffered(BufferOptions{MaxBytes: 100})
+	buffered.Info("first")
+	buffered.Info("second")
+	b.Close(true)
+
+	want := "warn - dropped 1 buffered events over memory limit\n" +
+		"info - second\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestBuffered_recorder(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{
+		Writer:    &buf,
+		Formatter: NewJournalFormatter(),
+		Level:     LevelInfo,
+		Recorder:  NewRecorder(10, LevelError),
+	})
+
+	buffered, b := log.Buffered(BufferOptions{})
+	buffered.Debug("loading")
+	buffered.Error("failed")
+	b.Close(true)
+
+	want := "debug - loading\n" +
+		"error - failed\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestContext(t *testing.T) {
+	log := NewWithName("context")
+	ctx := NewContext(context.Background(), log)
+
+	if FromContext(ctx) != log {
+		t.Errorf("expected logger from context")
+	}
+	if FromContext(context.Background()) == nil {
+		t.Errorf("expected discard logger for empty context")
+	}
+}
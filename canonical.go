@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+// Canonical accumulates fields during a unit of work, for example an HTTP request, and logs them as
+// a single wide event once the work is done. It is safe for concurrent use.
+type Canonical struct {
+	mu     sync.Mutex
+	fields []Field
+	index  map[string]int
+}
+
+// NewCanonical creates an empty canonical line.
+func NewCanonical() *Canonical {
+	return &Canonical{index: make(map[string]int)}
+}
+
+// Add adds fields to the canonical line, a field replaces the value of a previously added field with the same key.
+func (c *Canonical) Add(fields ...Field) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, f := range fields {
+		if i, ok := c.index[f.Key]; ok {
+			c.fields[i] = f
+			continue
+		}
+		c.index[f.Key] = len(c.fields)
+		c.fields = append(c.fields, f)
+	}
+}
+
+// Fields returns a copy of the accumulated fields in the order they were first added.
+func (c *Canonical) Fields() []Field {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Field(nil), c.fields...)
+}
+
+// Log writes the canonical line through the logger at the given level, using the formatter of the logger.
+func (c *Canonical) Log(l *Logger, lvl Level, message string) {
+	l.With(c.Fields()...).log(lvl, message, nil)
+}
+
+// canonicalKey is the key used to store the canonical line in a context.
+type canonicalKey struct{}
+
+// WithCanonical returns a copy of the context that carries the canonical line.
+func WithCanonical(ctx context.Context, c *Canonical) context.Context {
+	return context.WithValue(ctx, canonicalKey{}, c)
+}
+
+// CanonicalFromContext returns the canonical line carried by the context, or nil.
+func CanonicalFromContext(ctx context.Context) *Canonical {
+	c, _ := ctx.Value(canonicalKey{}).(*Canonical)
+	return c
+}
+
+// AddCanonical adds fields to the canonical line carried by the context. It does nothing when the context
+// does not carry a canonical line, so handlers may call it unconditionally.
+func AddCanonical(ctx context.Context, fields ...Field) {
+	if c := CanonicalFromContext(ctx); c != nil {
+		c.Add(fields...)
+	}
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter()})
+
+	c := NewCanonical()
+	ctx := WithCanonical(context.Background(), c)
+
+	AddCanonical(ctx, F("mandate", 42), F("status", "pending"))
+	AddCanonical(ctx, F("status", "signed"))
+	c.Log(log, LevelInfo, "canonical-log-line")
+
+	want := "info - canonical-log-line mandate=42 status=signed\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+
+	// no canonical line in context
+	AddCanonical(context.Background(), F("ignored", true))
+}
+
+func TestCanonical_concurrent(t *testing.T) {
+	c := NewCanonical()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Add(F("count", i))
+		}()
+	}
+	wg.Wait()
+
+	if n := len(c.Fields()); n != 1 {
+		t.Errorf("expected 1 field but got %d", n)
+	}
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DeterministicTime is the time of every event logged by a deterministic logger without a Clock.
+var DeterministicTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Clock provides the time of events. Samplers, limiters and budgets use the time of the event,
+// so a custom clock controls them as well.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the default clock which returns the current time.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always returns the same time.
+type FixedClock time.Time
+
+// NewFixedClock creates a clock that always returns t.
+func NewFixedClock(t time.Time) FixedClock {
+	return FixedClock(t)
+}
+
+// Now implements the Clock interface.
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// SteppingClock returns a time that advances by a fixed step every time it is read. It is safe for concurrent use.
+type SteppingClock struct {
+	mu   sync.Mutex
+	next time.Time
+	step time.Duration
+}
+
+// NewSteppingClock creates a clock that starts at start and advances by step after every call to Now.
+func NewSteppingClock(start time.Time, step time.Duration) *SteppingClock {
+	return &SteppingClock{next: start, step: step}
+}
+
+// Now implements the Clock interface.
+func (c *SteppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.next
+	c.next = c.next.Add(c.step)
+	return now
+}
+
+// Advance moves the clock forward by d without returning a time.
+func (c *SteppingClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.next = c.next.Add(d)
+	c.mu.Unlock()
+}
+
+// stripFields removes the fields with one of the keys from fields in place.
+func stripFields(fields []Field, keys []string) []Field {
+	n := 0
+	for _, f := range fields {
+		if !slices.Contains(keys, f.Key) {
+			fields[n] = f
+			n++
+		}
+	}
+	clear(fields[n:])
+	return fields[:n]
+}
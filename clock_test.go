@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSteppingClock(t *testing.T) {
+	start := time.Unix(10, 0)
+	clock := NewSteppingClock(start, time.Second)
+
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("\nWant: %s\nGot: %s", start, got)
+	}
+	clock.Advance(time.Minute)
+	if want, got := start.Add(61*time.Second), clock.Now(); !got.Equal(want) {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestLogger_clock(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := NewTextFormatter()
+	log := NewWithOptions(Options{
+		Writer:    &buf,
+		Formatter: formatter,
+		Clock:     NewFixedClock(time.Unix(1, 0)),
+	})
+
+	log.Info("hello")
+	want := "ts=1000 lvl=info msg=hello\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+}
+
+func TestLogger_deterministic(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{
+		Writer:        &buf,
+		Deterministic: true,
+	})
+
+	log.With(F(TraceIDField, "4bf92f3577b34da6a3ce929d0e0e4736"), F("mandate", 42), F("pid", 1234)).Info("hello")
+	log.Info("world")
+
+	want := "ts=946684800000 lvl=info msg=hello mandate=42\n" +
+		"ts=946684800000 lvl=info msg=world\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+}
+
+func TestLogger_processFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{
+		Writer:        &buf,
+		Deterministic: true,
+		ProcessFields: []string{"host"},
+	})
+
+	log.With(F("host", "web-1"), F("duration", "1d")).Info("hello")
+
+	want := "ts=946684800000 lvl=info msg=hello duration=1d\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// collapser detects identical consecutive events and holds their repeats,
+// similar to the "last message repeated N times" lines of syslogd. Events are identical when their level, module,
+// message and fields are equal, so an event with other field values is written and not held as a repeat.
+type collapser struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	wall    bool // the logger uses the system clock, so idle repeats can be flushed by a timer
+	timer   *time.Timer
+
+	// last written event
+	logger  *Logger
+	level   Level
+	module  string
+	message string
+	fields  []Field
+	repeats int
+	since   time.Time // time of the first held repeat
+	buf     []byte    // scratch buffer to compare field values
+}
+
+// newCollapser creates a collapser that flushes held repeats after the timeout. The timeout is measured with the
+// time of the events, held repeats of an idle logger are only flushed by a timer when it uses the system clock.
+func newCollapser(timeout time.Duration, clock Clock) *collapser {
+	return &collapser{timeout: timeout, wall: clock == SystemClock}
+}
+
+// write writes the event unless it repeats the previously written event.
+func (c *collapser) write(l *Logger, e *Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.logger != nil && e.Level == c.level && e.Module == c.module && e.Message == c.message && c.sameFields(e.Fields) {
+		if c.repeats > 0 && c.timeout > 0 && e.Time.Sub(c.since) >= c.timeout {
+			c.flushLocked()
+		}
+		c.repeats++
+		if c.repeats == 1 {
+			c.since = e.Time
+			c.startTimer()
+		}
+		if e.recorder != nil {
+			e.recorder.written(e) // written as part of the repeat summary
+		}
+		return // hold repeat
+	}
+
+	c.flushLocked()
+	c.logger = l
+	c.level = e.Level
+	c.module = e.Module
+	c.message = e.Message
+	c.fields = append(c.fields[:0], e.Fields...)
+	l.write(e)
+}
+
+// sameFields returns true when the fields have the same keys and values as the last written event.
+// Values are compared by their text, so values that are not comparable never panic.
+func (c *collapser) sameFields(fields []Field) bool {
+	if len(fields) != len(c.fields) {
+		return false
+	}
+	for i, f := range fields {
+		if f.Key != c.fields[i].Key {
+			return false
+		}
+		c.buf = appendValue(c.buf[:0], f.Value)
+		n := len(c.buf)
+		c.buf = appendValue(c.buf, c.fields[i].Value)
+		if !bytes.Equal(c.buf[:n], c.buf[n:]) {
+			return false
+		}
+	}
+	return true
+}
+
+// flush writes the number of held repeats.
+func (c *collapser) flush() {
+	c.mu.Lock()
+	c.flushLocked()
+	c.mu.Unlock()
+}
+
+func (c *collapser) flushLocked() {
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	if c.repeats == 0 {
+		return
+	}
+
+	e := c.logger.event(c.level, fmt.Sprintf("last message repeated %d times", c.repeats))
+	e.Module = c.module
+	c.logger.write(e)
+	putEvent(e)
+	c.repeats = 0
+}
+
+func (c *collapser) startTimer() {
+	if c.timeout <= 0 || !c.wall {
+		return
+	}
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.timeout, c.flush)
+	} else {
+		c.timer.Reset(c.timeout)
+	}
+}
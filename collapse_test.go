@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollapseRepeats(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := NewJournalFormatter()
+	log := NewWithOptions(Options{Writer: &buf, Formatter: formatter, CollapseRepeats: time.Hour})
+
+	log.Error("health check failed")
+	log.Error("health check failed")
+	log.Error("health check failed")
+	log.Info("health check succeeded")
+	log.Info("health check succeeded")
+	log.Flush()
+
+	want := "error - health check failed\n" +
+		"error - last message repeated 2 times\n" +
+		"info - health check succeeded\n" +
+		"info - last message repeated 1 times\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestCollapseRepeats_fields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter(), CollapseRepeats: time.Hour})
+
+	log.With(F("customer", 1)).Error("failed")
+	log.With(F("customer", 1)).Error("failed")
+	log.With(F("customer", 2)).Error("failed")
+	log.With(F("tags", []string{"b2b"})).Error("failed")
+	log.With(F("tags", []string{"b2b"})).Error("failed")
+	log.Flush()
+
+	want := "error - failed customer=1\n" +
+		"error - last message repeated 1 times customer=1\n" +
+		"error - failed customer=2\n" +
+		"error - failed tags=[b2b]\n" +
+		"error - last message repeated 1 times tags=[b2b]\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestCollapseRepeats_timeout(t *testing.T) {
+	var buf syncBuffer
+	log := NewWithOptions(Options{Writer: &buf, CollapseRepeats: time.Millisecond})
+
+	log.Error("health check failed")
+	log.Error("health check failed")
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "repeated 1 times") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected repeats to be flushed after timeout, got: %s", buf.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCollapseRepeats_clock(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{
+		Writer:          &buf,
+		Formatter:       NewJournalFormatter(),
+		Clock:           NewSteppingClock(DeterministicTime, 20*time.Second),
+		CollapseRepeats: 30 * time.Second,
+	})
+
+	for i := 0; i < 4; i++ {
+		log.Error("health check failed")
+	}
+	log.Flush()
+
+	// the repeats at 20s and 40s are summarized by the repeat at 60s, which is 40s after the first held repeat
+	want := "error - health check failed\n" +
+		"error - last message repeated 2 times\n" +
+		"error - last message repeated 1 times\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
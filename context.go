@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"context"
+)
+
+// contextKey is the key used to store the logger in a context.
+type contextKey struct{}
+
+// discard is returned by FromContext when the context does not contain a logger.
+var discard = NewWithOptions(Options{})
+
+// NewContext returns a copy of the context that carries the logger.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by the context. It returns a logger that discards
+// every line when the context does not carry a logger.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+	return discard
+}
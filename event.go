@@ -9,12 +9,20 @@ import (
 // The Formatter of the logger will further handle this event to write this to the io.Writer.
 type Event struct {
 	buf      []byte
+	text     textEncoder // reused to encode objects without allocating
+	json     jsonEncoder
 	Time     time.Time
 	Module   string
 	Level    Level
 	Line     int
 	Filename string
 	Message  string
+	Template string // message template of which the message was rendered, if any
+	Fields   []Field
+
+	args     []interface{} // template arguments that are not yet rendered
+	recorder *Recorder     // recorder that holds a copy of the event, if any
+	recorded uint64        // sequence number of the copy in the recorder
 }
 
 // eventPool is used to efficiently make use of our internal buffer.
@@ -39,6 +47,8 @@ func putEvent(e *Event) {
 	if cap(e.buf) > maxSize {
 		return
 	}
+	clear(e.Fields) // release references to field values
+	e.args = nil
 	eventPool.Put(e)
 }
 
@@ -46,5 +56,20 @@ func putEvent(e *Event) {
 func getEvent() *Event {
 	e := eventPool.Get().(*Event)
 	e.buf = e.buf[:0] // truncate buffer
+	e.Template = ""
+	e.args = nil
+	e.recorder = nil
+	e.Fields = e.Fields[:0]
 	return e
 }
+
+// Clone returns a copy of the event that is safe to keep after the event was returned to the pool.
+// The formatted buffer and encoder state are not copied.
+func (e *Event) Clone() *Event {
+	clone := *e
+	clone.buf = nil
+	clone.text = textEncoder{}
+	clone.json = jsonEncoder{}
+	clone.Fields = append([]Field(nil), e.Fields...)
+	return &clone
+}
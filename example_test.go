@@ -0,0 +1,74 @@
+package logger_test
+
+import (
+	"os"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+func ExampleNewWithOptions() {
+	log := logger.NewWithOptions(logger.Options{
+		Name:          "my-logger",
+		Writer:        os.Stdout,
+		Level:         logger.LevelTrace,
+		Deterministic: true,
+	})
+
+	log.Info("hello world")
+	// Output: ts=946684800000 logger=my-logger lvl=info msg="hello world"
+}
+
+func ExampleLogger_With() {
+	log := logger.NewWithOptions(logger.Options{
+		Name:          logger.DefaultLoggerName,
+		Writer:        os.Stdout,
+		Deterministic: true,
+	}).With(logger.F("mandate", 42))
+
+	log.Info("mandate signed")
+	// Output: ts=946684800000 logger=default lvl=info msg="mandate signed" mandate=42
+}
+
+func ExampleNewCountSampler() {
+	log := logger.NewWithOptions(logger.Options{
+		Writer:  os.Stdout,
+		Sampler: logger.NewCountSampler(time.Second, 2, 0),
+		Clock:   logger.NewSteppingClock(logger.DeterministicTime, 300*time.Millisecond),
+	})
+
+	for i := 0; i < 5; i++ {
+		log.Error("health check failed")
+	}
+	// Output:
+	// ts=946684800000 lvl=error msg="health check failed"
+	// ts=946684800300 lvl=error msg="health check failed"
+	// ts=946684801200 lvl=error msg="suppressed 2 similar events" sample_key="health check failed" suppressed=2
+	// ts=946684801200 lvl=error msg="health check failed"
+}
+
+func ExampleOptions_collapseRepeats() {
+	log := logger.NewWithOptions(logger.Options{
+		Writer:          os.Stdout,
+		CollapseRepeats: time.Minute,
+		Deterministic:   true,
+	})
+
+	for i := 0; i < 12; i++ {
+		log.Error("health check failed")
+	}
+	log.Flush()
+	// Output:
+	// ts=946684800000 lvl=error msg="health check failed"
+	// ts=946684800000 lvl=error msg="last message repeated 11 times"
+}
+
+func ExampleOptions_deterministic() {
+	log := logger.NewWithOptions(logger.Options{
+		Writer:        os.Stdout,
+		Deterministic: true,
+	})
+
+	log.With(logger.F("request_id", "01J9ZQ"), logger.F("duration", 12*time.Millisecond), logger.F("status", 200)).Info("request done")
+	// Output: ts=946684800000 lvl=info msg="request done" status=200
+}
@@ -0,0 +1,101 @@
+// Package execlog captures the output of subprocesses and logs it line by line through the logger.
+package execlog
+
+import (
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+// Field names of captured lines and the exit line.
+const (
+	FieldStream   = "stream"
+	FieldPartial  = logger.PartialField
+	FieldExitCode = "exit_code"
+	FieldDuration = "duration"
+	FieldError    = "error"
+)
+
+// Options configures how the output of a command is logged.
+type Options struct {
+	// StdoutLevel is the level of lines written to stdout. Defaults to LevelInfo.
+	StdoutLevel logger.Level
+
+	// StderrLevel is the level of lines written to stderr. Defaults to LevelWarning.
+	StderrLevel logger.Level
+
+	// MaxLineLength splits lines that are longer, the parts are logged with the partial field. Defaults to 8KiB.
+	MaxLineLength int
+}
+
+// Capture logs the output of a command that was attached using Attach.
+type Capture struct {
+	cmd    *exec.Cmd
+	l      *logger.Logger
+	stdout *logger.LineWriter
+	stderr *logger.LineWriter
+	start  time.Time
+}
+
+// Attach sets the stdout and stderr of the command to log every line under a child logger named after the command.
+// Partial lines are logged when the command finishes. Use Capture.Start and Capture.Wait, or Run, instead of the
+// methods of the command to also log the exit status and duration.
+func Attach(cmd *exec.Cmd, l *logger.Logger, opts Options) *Capture {
+	if opts.StdoutLevel <= 0 {
+		opts.StdoutLevel = logger.LevelInfo
+	}
+	if opts.StderrLevel <= 0 {
+		opts.StderrLevel = logger.LevelWarning
+	}
+
+	l = l.WithName(filepath.Base(cmd.Path))
+	c := &Capture{
+		cmd:    cmd,
+		l:      l,
+		stdout: logger.NewLineWriter(l.With(logger.F(FieldStream, "stdout")), opts.StdoutLevel, opts.MaxLineLength),
+		stderr: logger.NewLineWriter(l.With(logger.F(FieldStream, "stderr")), opts.StderrLevel, opts.MaxLineLength),
+	}
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+	return c
+}
+
+// Run attaches the logger to the command, runs it and waits for it to finish.
+func Run(cmd *exec.Cmd, l *logger.Logger, opts Options) error {
+	c := Attach(cmd, l, opts)
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Start starts the command and logs an error when it could not be started.
+func (c *Capture) Start() error {
+	c.start = c.l.Clock().Now()
+	if err := c.cmd.Start(); err != nil {
+		c.l.With(logger.F(FieldError, err)).Error("command failed to start")
+		return err
+	}
+	return nil
+}
+
+// Wait waits for the command to finish, logs the remaining partial lines and the exit status and duration.
+// A non-zero exit status is logged at LevelError.
+func (c *Capture) Wait() error {
+	err := c.cmd.Wait()
+	c.stdout.Flush()
+	c.stderr.Flush()
+
+	fields := []logger.Field{
+		logger.F(FieldExitCode, c.cmd.ProcessState.ExitCode()),
+		logger.F(FieldDuration, c.l.Clock().Now().Sub(c.start)),
+	}
+	if err != nil {
+		c.l.With(append(fields, logger.F(FieldError, err))...).Error("command failed")
+	} else {
+		c.l.With(fields...).Info("command finished")
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package execlog
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/twikey/go-logger"
+)
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	var buf syncBuffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf, Formatter: logger.NewJournalFormatter()})
+
+	cmd := exec.Command("sh", "-c", "echo hello; echo oops >&2; printf partial; exit 3")
+	if err := Run(cmd, l, Options{}); err == nil {
+		t.Fatal("expected exit error")
+	}
+
+	got := buf.String()
+	for _, want := range []string{
+		"[sh] info - hello stream=stdout\n",
+		"[sh] warn - oops stream=stderr\n",
+		"[sh] info - partial stream=stdout\n",
+		"[sh] error - command failed exit_code=3 duration=",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output: %s", want, got)
+		}
+	}
+}
+
+// syncBuffer is a bytes.Buffer that can be used by the concurrent stdout and stderr writers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Field is a key value pair that is attached to an Event next to its message.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F creates a new Field with the given key and value.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// appendValue appends the textual representation of value to dst.
+// Common types are appended without allocating, all other types are formatted using the fmt package.
+func appendValue(dst []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return append(dst, v...)
+	case []byte:
+		return append(dst, v...)
+	case int:
+		return strconv.AppendInt(dst, int64(v), 10)
+	case int8:
+		return strconv.AppendInt(dst, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(dst, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(dst, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(dst, v, 10)
+	case uint:
+		return strconv.AppendUint(dst, uint64(v), 10)
+	case uint8:
+		return strconv.AppendUint(dst, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(dst, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(dst, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(dst, v, 10)
+	case float32:
+		return strconv.AppendFloat(dst, float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.AppendFloat(dst, v, 'g', -1, 64)
+	case bool:
+		return strconv.AppendBool(dst, v)
+	case time.Duration:
+		return append(dst, v.String()...)
+	case time.Time:
+		return v.AppendFormat(dst, time.RFC3339Nano)
+	case ObjectMarshaler:
+		enc := &compactEncoder{buf: dst}
+		enc.object(v)
+		return enc.buf
+	case ArrayMarshaler:
+		enc := &compactEncoder{buf: dst}
+		enc.array(v)
+		return enc.buf
+	case error:
+		return append(dst, v.Error()...)
+	case fmt.Stringer:
+		return append(dst, v.String()...)
+	case nil:
+		return append(dst, "<nil>"...)
+	default:
+		return fmt.Append(dst, v)
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"bytes"
 	"fmt"
 	"strconv"
 	"strings"
@@ -19,6 +20,7 @@ const (
 	bracketRight = "]"
 	hyphen       = "-"
 	reset        = "\033[0m"
+	hexDigits    = "0123456789abcdef"
 )
 
 const (
@@ -68,6 +70,9 @@ type Formatter interface {
 type PrettyFormatter struct {
 	TimeFormat   string
 	AppendSource bool
+
+	// Sanitize defines how control characters in the module, message and fields are written.
+	Sanitize SanitizeMode
 }
 
 // NewPrettyFormatter  creates a new instance of the PrettyFormatter which output log lines in a pretty format.
@@ -96,7 +101,23 @@ func (s *PrettyFormatter) Format(event *Event) {
 		s.color(event, bold, event.Message)
 	} else {
 		// normal output of message
-		event.buf = append(event.buf, event.Message...)
+		event.buf = s.Sanitize.AppendSanitized(event.buf, event.Message)
+	}
+
+	// append fields, except the ones already shown in a rendered template
+	var template *messageTemplate
+	if event.Template != "" {
+		template = parseTemplate(event.Template)
+	}
+	for _, f := range event.Fields {
+		if template != nil && template.captures(f.Key) {
+			continue
+		}
+		event.buf = append(event.buf, space)
+		s.color(event, cyan, f.Key+"=")
+		start := len(event.buf)
+		event.buf = appendValue(event.buf, f.Value)
+		event.buf = s.Sanitize.sanitize(event.buf, start)
 	}
 
 	// append source information
@@ -115,21 +136,24 @@ func (s *PrettyFormatter) color(e *Event, color int, value string) {
 	if color > 0 {
 		code := fmt.Sprintf("\x1b[%dm", color)
 		e.buf = append(e.buf, code...)
-		e.buf = append(e.buf, value...)
+		e.buf = s.Sanitize.AppendSanitized(e.buf, value)
 		e.buf = append(e.buf, reset...)
 	} else {
 		// without any color
-		e.buf = append(e.buf, value...)
+		e.buf = s.Sanitize.AppendSanitized(e.buf, value)
 	}
 }
 
 // TextFormatter is a performance focused formatter prints out the log lines in a logfmt style.
+// Values are quoted and escaped when needed, including control characters and invalid UTF-8, and invalid characters
+// in keys are replaced by an underscore, so every line can be parsed back into the original keys and values.
 type TextFormatter struct {
 	// field names
 	TimestampField string
 	LevelField     string
 	MessageField   string
 	NameField      string
+	TemplateField  string // written after the message when it was rendered of a template
 }
 
 // NewTextFormatter creates a new instance of the TextFormatter which outputs log lines in logfmt style.
@@ -139,20 +163,34 @@ func NewTextFormatter() *TextFormatter {
 		LevelField:     "lvl",
 		MessageField:   "msg",
 		NameField:      "logger",
+		TemplateField:  "template",
 	}
 }
 
 func (t *TextFormatter) Format(event *Event) {
-	t.encode(event, t.TimestampField, event.Time.UnixMilli(), false)
+	t.encode(event, t.TimestampField, event.Time.UnixMilli())
 	if event.Module != "" {
 		// only write module when not empty
-		t.encode(event, t.NameField, event.Module, false)
+		t.encode(event, t.NameField, event.Module)
+	}
+	t.encode(event, t.LevelField, event.Level.String())
+	t.encode(event, t.MessageField, event.Message)
+	if event.Template != "" {
+		t.encode(event, t.TemplateField, event.Template)
+	}
+	for _, f := range event.Fields {
+		t.encodeField(event, f)
+	}
+
+	// replace the trailing space with a newline
+	if n := len(event.buf); n > 0 && event.buf[n-1] == space {
+		event.buf[n-1] = newline
+	} else {
+		event.buf = append(event.buf, newline)
 	}
-	t.encode(event, t.LevelField, event.Level.String(), false)
-	t.encode(event, t.MessageField, event.Message, true)
 }
 
-func (t *TextFormatter) encode(e *Event, key string, value interface{}, eol bool) {
+func (t *TextFormatter) encode(e *Event, key string, value interface{}) {
 	if key == "" {
 		return // skip encoding -> key is empty.
 	}
@@ -166,21 +204,54 @@ func (t *TextFormatter) encode(e *Event, key string, value interface{}, eol bool
 	case int64:
 		t.valueInt64(e, v)
 	case string:
-
 		t.valueString(e, v)
 	}
 
-	if eol {
-		// write newline
-		e.buf = append(e.buf, byte(newline))
-	} else {
-		// write space
-		e.buf = append(e.buf, byte(space))
+	// write space
+	e.buf = append(e.buf, byte(space))
+}
+
+// encodeField encodes a field of any value, separated from encode to avoid fixed values escaping to the heap.
+func (t *TextFormatter) encodeField(e *Event, f Field) {
+	if f.Key == "" {
+		return // skip encoding -> key is empty.
+	}
+
+	switch v := f.Value.(type) {
+	case ObjectMarshaler:
+		e.text.reset(t, e).AddObject(f.Key, v)
+		return
+	case ArrayMarshaler:
+		e.text.reset(t, e).AddArray(f.Key, v)
+		return
 	}
+
+	t.key(e, f.Key)
+	t.equal(e)
+	t.valueAny(e, f.Value)
+	e.buf = append(e.buf, byte(space))
 }
 
 func (t *TextFormatter) key(e *Event, key string) {
-	e.buf = append(e.buf, key...)
+	e.buf = t.appendKey(e.buf, key)
+}
+
+// appendKey appends the key, replacing the characters that are not allowed in a logfmt key by an underscore.
+func (t *TextFormatter) appendKey(dst []byte, key string) []byte {
+	if t.validKey(key) {
+		return append(dst, key...) // fast path -> key is valid
+	}
+
+	for i := 0; i < len(key); {
+		r, size := utf8.DecodeRuneInString(key[i:])
+		if t.invalidKeyRune(r) {
+			dst = append(dst, '_')
+		} else {
+			dst = append(dst, key[i:i+size]...)
+		}
+		i += size
+	}
+	return dst
 }
 
 func (t *TextFormatter) equal(e *Event) {
@@ -188,27 +259,103 @@ func (t *TextFormatter) equal(e *Event) {
 }
 
 func (t *TextFormatter) valueString(e *Event, value string) {
-	if strings.IndexFunc(value, t.needsQuotedValueRune) != -1 {
-		e.buf = append(e.buf, byte(quote))
-		e.buf = append(e.buf, value...)
-		e.buf = append(e.buf, byte(quote))
-	} else {
-		e.buf = append(e.buf, value...)
+	start := len(e.buf)
+	e.buf = append(e.buf, value...)
+	if needsQuote(e.buf[start:]) {
+		e.buf = appendQuoted(e.buf, start)
+	}
+}
+
+// valueAny appends any field value and quotes it afterward when required.
+func (t *TextFormatter) valueAny(e *Event, value interface{}) {
+	start := len(e.buf)
+	e.buf = appendValue(e.buf, value)
+	if needsQuote(e.buf[start:]) {
+		e.buf = appendQuoted(e.buf, start)
+	}
+}
+
+// appendQuoted replaces the value appended from start by its quoted and escaped form. Quotes and backslashes are
+// escaped with a backslash, control characters as \n, \r, \t or \u00XX and invalid UTF-8 as \ufffd.
+func appendQuoted(buf []byte, start int) []byte {
+	end := len(buf)
+	raw := buf[start:end] // stays valid when appending reallocates the buffer
+
+	buf = append(buf, quote)
+	safe := 0 // start of the bytes that are not yet appended
+	for i := 0; i < len(raw); {
+		if c := raw[i]; c < utf8.RuneSelf && c >= ' ' && c != quote && c != '\\' && c != 0x7f {
+			i++
+			continue // fast path -> printable ascii is appended in bulk
+		}
+
+		buf = append(buf, raw[safe:i]...)
+		r, size := utf8.DecodeRune(raw[i:])
+		switch {
+		case r == quote || r == '\\':
+			buf = append(buf, '\\', byte(r))
+		case r == '\n':
+			buf = append(buf, '\\', 'n')
+		case r == '\r':
+			buf = append(buf, '\\', 'r')
+		case r == '\t':
+			buf = append(buf, '\\', 't')
+		case isControl(r):
+			buf = append(buf, '\\', 'u', '0', '0', hexDigits[r>>4], hexDigits[r&0xf])
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, `\ufffd`...)
+		default:
+			buf = append(buf, raw[i:i+size]...)
+		}
+		i += size
+		safe = i
 	}
+	buf = append(buf, raw[safe:]...)
+	buf = append(buf, quote)
+
+	// move the quoted value over the raw value
+	n := copy(buf[start:], buf[end:])
+	return buf[:start+n]
 }
 
 func (t *TextFormatter) valueInt64(e *Event, value int64) {
 	e.buf = strconv.AppendInt(e.buf, value, 10)
 }
 
-func (t *TextFormatter) needsQuotedValueRune(r rune) bool {
-	return r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError
+func needsQuotedValueRune(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || isControl(r) || r == utf8.RuneError
+}
+
+// needsQuote reports whether the value must be quoted, without decoding runes for ascii values.
+func needsQuote(value []byte) bool {
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c <= ' ' || c == equal || c == quote || c >= 0x7f {
+			return c < utf8.RuneSelf || bytes.IndexFunc(value[i:], needsQuotedValueRune) != -1
+		}
+	}
+	return false
+}
+
+// validKey reports whether the key can be written as is, without decoding runes for ascii keys.
+func (t *TextFormatter) validKey(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c <= ' ' || c == equal || c == quote || c >= 0x7f {
+			return c >= utf8.RuneSelf && strings.IndexFunc(key, t.invalidKeyRune) == -1
+		}
+	}
+	return true
+}
+
+func (t *TextFormatter) invalidKeyRune(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || isControl(r) || r == utf8.RuneError
 }
 
 // JournalFormatter is a formatter which prints log lines in the following output:
 //
 // [module] level - message
 type JournalFormatter struct {
+	// Sanitize defines how control characters in the module, message and fields are written.
+	Sanitize SanitizeMode
 }
 
 // NewJournalFormatter creates a new formatter which outputs log lines in a journalctl friendly format.
@@ -219,7 +366,7 @@ func NewJournalFormatter() *JournalFormatter {
 func (j *JournalFormatter) Format(e *Event) {
 	if e.Module != "" {
 		e.buf = append(e.buf, bracketLeft...)
-		e.buf = append(e.buf, e.Module...)
+		e.buf = j.Sanitize.AppendSanitized(e.buf, e.Module)
 		e.buf = append(e.buf, bracketRight...)
 		e.buf = append(e.buf, space)
 	}
@@ -228,6 +375,167 @@ func (j *JournalFormatter) Format(e *Event) {
 	e.buf = append(e.buf, space)
 	e.buf = append(e.buf, hyphen...)
 	e.buf = append(e.buf, space)
-	e.buf = append(e.buf, e.Message...)
+	e.buf = j.Sanitize.AppendSanitized(e.buf, e.Message)
+	for _, f := range e.Fields {
+		e.buf = append(e.buf, space)
+		e.buf = j.Sanitize.AppendSanitized(e.buf, f.Key)
+		e.buf = append(e.buf, equal)
+		start := len(e.buf)
+		e.buf = appendValue(e.buf, f.Value)
+		e.buf = j.Sanitize.sanitize(e.buf, start)
+	}
 	e.buf = append(e.buf, newline)
 }
+
+// CommonLogFormatter is a formatter which prints access log lines in the Apache Common or Combined Log Format:
+//
+// remote_addr - user [time] "method uri proto" status bytes "referer" "user_agent"
+//
+// The request information is read from the event fields, missing values are written as a hyphen.
+// Events without a status field, such as the lines of a handler that carry the method of the request,
+// are written with their message in place of the request line.
+type CommonLogFormatter struct {
+	// Combined appends the referer and user agent as in the Combined Log Format.
+	Combined bool
+
+	// field names
+	RemoteAddrField string
+	UserField       string
+	MethodField     string
+	URIField        string
+	ProtoField      string
+	StatusField     string
+	BytesField      string
+	RefererField    string
+	UserAgentField  string
+
+	// Sanitize defines how control characters in the request line and fields are written.
+	// SanitizeEscape writes them as \xXX bytes like Apache does.
+	Sanitize SanitizeMode
+}
+
+// NewCommonLogFormatter creates a new formatter which outputs log lines in the Apache Common Log Format.
+// The default field names match the access log fields of the httplog package.
+func NewCommonLogFormatter() *CommonLogFormatter {
+	return &CommonLogFormatter{
+		RemoteAddrField: "remote_addr",
+		UserField:       "user",
+		MethodField:     "method",
+		URIField:        "uri",
+		ProtoField:      "proto",
+		StatusField:     "status",
+		BytesField:      "bytes",
+		RefererField:    "referer",
+		UserAgentField:  "user_agent",
+	}
+}
+
+// NewCombinedLogFormatter creates a new formatter which outputs log lines in the Apache Combined Log Format.
+func NewCombinedLogFormatter() *CommonLogFormatter {
+	f := NewCommonLogFormatter()
+	f.Combined = true
+	return f
+}
+
+func (c *CommonLogFormatter) Format(e *Event) {
+	c.value(e, c.RemoteAddrField)
+	e.buf = append(e.buf, " - "...)
+	c.value(e, c.UserField)
+	e.buf = append(e.buf, space)
+	e.buf = append(e.buf, bracketLeft...)
+	e.buf = e.Time.AppendFormat(e.buf, "02/Jan/2006:15:04:05 -0700")
+	e.buf = append(e.buf, bracketRight...)
+	e.buf = append(e.buf, space, quote)
+	if _, ok := c.field(e, c.StatusField); ok {
+		c.value(e, c.MethodField)
+		e.buf = append(e.buf, space)
+		c.value(e, c.URIField)
+		e.buf = append(e.buf, space)
+		c.value(e, c.ProtoField)
+	} else {
+		start := len(e.buf)
+		e.buf = append(e.buf, e.Message...)
+		c.escape(e, start)
+	}
+	e.buf = append(e.buf, quote, space)
+	c.value(e, c.StatusField)
+	e.buf = append(e.buf, space)
+	if v, ok := c.field(e, c.BytesField); ok && !isZero(v) {
+		e.buf = appendValue(e.buf, v)
+	} else {
+		e.buf = append(e.buf, hyphen...)
+	}
+
+	if c.Combined {
+		e.buf = append(e.buf, space, quote)
+		c.value(e, c.RefererField)
+		e.buf = append(e.buf, quote, space, quote)
+		c.value(e, c.UserAgentField)
+		e.buf = append(e.buf, quote)
+	}
+	e.buf = append(e.buf, newline)
+}
+
+// field returns the value of the field with the given key.
+func (c *CommonLogFormatter) field(e *Event, key string) (interface{}, bool) {
+	if key == "" {
+		return nil, false
+	}
+	for i := len(e.Fields) - 1; i >= 0; i-- {
+		if e.Fields[i].Key == key {
+			return e.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// value appends the value of the field with the given key, or a hyphen when the field is missing or empty.
+func (c *CommonLogFormatter) value(e *Event, key string) {
+	v, ok := c.field(e, key)
+	if !ok || v == "" {
+		e.buf = append(e.buf, hyphen...)
+		return
+	}
+	start := len(e.buf)
+	e.buf = appendValue(e.buf, v)
+	c.escape(e, start)
+}
+
+// escape escapes the quotes and backslashes of the value appended from start like Apache does in access logs,
+// and sanitizes control characters, ANSI CSI sequences and invalid UTF-8 according to the sanitize mode.
+func (c *CommonLogFormatter) escape(e *Event, start int) {
+	if bytes.IndexAny(e.buf[start:], `"\`) >= 0 {
+		end := len(e.buf)
+		raw := e.buf[start:end] // stays valid when appending reallocates the buffer
+		safe := 0               // start of the bytes that are not yet appended
+		for i, b := range raw {
+			if b == quote || b == '\\' {
+				e.buf = append(e.buf, raw[safe:i]...)
+				e.buf = append(e.buf, '\\', b)
+				safe = i + 1
+			}
+		}
+		e.buf = append(e.buf, raw[safe:]...)
+
+		// move the escaped value over the raw value
+		n := copy(e.buf[start:], e.buf[end:])
+		e.buf = e.buf[:start+n]
+	}
+
+	mode := c.Sanitize
+	if mode == SanitizeEscape {
+		mode = sanitizeHex
+	}
+	e.buf = mode.sanitize(e.buf, start)
+}
+
+// isZero returns true for integer zero values.
+func isZero(v interface{}) bool {
+	switch n := v.(type) {
+	case int:
+		return n == 0
+	case int64:
+		return n == 0
+	}
+	return false
+}
@@ -2,9 +2,13 @@ package logger
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func TestJournalFormatter_noModule(t *testing.T) {
@@ -71,9 +75,6 @@ func TestTextFormatter_quotable(t *testing.T) {
 }
 
 func TestTextFormatter_escapeCharacters(t *testing.T) {
-	// TODO: not yet implemented
-	t.SkipNow()
-
 	formatter := NewTextFormatter()
 	e := &Event{
 		buf:      make([]byte, 0, 500),
@@ -91,6 +92,154 @@ func TestTextFormatter_escapeCharacters(t *testing.T) {
 	}
 }
 
+func TestTextFormatter_escape(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "plain", want: "plain"},
+		{value: "he said \"hi\"\n", want: `"he said \"hi\"\n"`},
+		{value: `C:\temp dir`, want: `"C:\\temp dir"`},
+		{value: "a\tb\rc", want: `"a\tb\rc"`},
+		{value: "bell\x07", want: `"bell\u0007"`},
+		{value: "del\x7f", want: `"del\u007f"`},
+		{value: "bad\xffutf8", want: `"bad\ufffdutf8"`},
+		{value: "key=value", want: `"key=value"`},
+		{value: "héllo wörld", want: `"héllo wörld"`},
+	}
+
+	formatter := &TextFormatter{MessageField: "msg"}
+	for _, tt := range tests {
+		e := &Event{Message: tt.value}
+		formatter.Format(e)
+		if want := "msg=" + tt.want + "\n"; want != string(e.buf) {
+			t.Errorf("\nWant: %sGot: %s", want, string(e.buf))
+		}
+	}
+}
+
+func TestTextFormatter_escapeFields(t *testing.T) {
+	formatter := &TextFormatter{MessageField: "msg"}
+	e := &Event{
+		Message: "hello",
+		Fields:  []Field{F("user name", "o\"brien"), F("a=b", 42), F("bad\xffkey", errors.New("line\nbreak"))},
+	}
+
+	want := `msg=hello user_name="o\"brien" a_b=42 bad_key="line\nbreak"` + "\n"
+	formatter.Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %sGot: %s", want, string(e.buf))
+	}
+}
+
+func FuzzTextFormatter(f *testing.F) {
+	f.Add("hello", "key", "value")
+	f.Add("he said \"hi\"\n", "user name", `C:\temp`)
+	f.Add("bad\xffutf8\x00", "a=b", "\x1b[31mred")
+	f.Add("", "", "\u2028")
+
+	formatter := &TextFormatter{MessageField: "msg"}
+	f.Fuzz(func(t *testing.T, message, key, value string) {
+		e := &Event{Message: message, Fields: []Field{F(key, value)}}
+		formatter.Format(e)
+
+		line := string(e.buf)
+		if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+			t.Fatalf("line must end with its only newline: %q", line)
+		}
+
+		pairs, err := decodeLogfmt(strings.TrimSuffix(line, "\n"))
+		if err != nil {
+			t.Fatalf("%v: %q", err, line)
+		}
+
+		want := [][2]string{{"msg", validUTF8(message)}}
+		if key != "" {
+			want = append(want, [2]string{sanitizeKey(key), validUTF8(value)})
+		}
+		if len(pairs) != len(want) {
+			t.Fatalf("\nWant: %q\nGot: %q", want, pairs)
+		}
+		for i := range want {
+			if pairs[i] != want[i] {
+				t.Errorf("\nWant: %q\nGot: %q", want[i], pairs[i])
+			}
+		}
+	})
+}
+
+// decodeLogfmt parses a logfmt line with quoted values escaped as Go string literals.
+func decodeLogfmt(line string) ([][2]string, error) {
+	if strings.IndexFunc(line, func(r rune) bool { return r < ' ' || r == 0x7f }) >= 0 {
+		return nil, fmt.Errorf("unescaped control character")
+	}
+
+	var pairs [][2]string
+	for line != "" {
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("missing key")
+		}
+		key := line[:eq]
+		if strings.ContainsAny(key, " \"") {
+			return nil, fmt.Errorf("invalid key %q", key)
+		}
+		line = line[eq+1:]
+
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, fmt.Errorf("unterminated value")
+			}
+			unquoted, err := strconv.Unquote(line[:end+1])
+			if err != nil {
+				return nil, err
+			}
+			value, line = unquoted, line[end+1:]
+		} else if sp := strings.IndexByte(line, ' '); sp >= 0 {
+			value, line = line[:sp], line[sp:]
+		} else {
+			value, line = line, ""
+		}
+		pairs = append(pairs, [2]string{key, value})
+		if line != "" {
+			if line[0] != ' ' {
+				return nil, fmt.Errorf("missing separator")
+			}
+			line = line[1:]
+		}
+	}
+	return pairs, nil
+}
+
+// validUTF8 replaces every invalid byte by the replacement character, like the formatter does.
+func validUTF8(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteRune(r)
+		i += size
+	}
+	return b.String()
+}
+
+// sanitizeKey replaces the characters that are not allowed in a key by an underscore, like the formatter does.
+func sanitizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || isControl(r) || r == utf8.RuneError {
+			return '_'
+		}
+		return r
+	}, validUTF8(key))
+}
+
 func BenchmarkTextFormatter(b *testing.B) {
 	formatter := NewTextFormatter()
 
@@ -107,6 +256,8 @@ func BenchmarkTextFormatter(b *testing.B) {
 		}
 
 		for pb.Next() {
+			e.buf = e.buf[:0]
+			e.buf = e.buf[:0]
 			formatter.Format(e)
 		}
 	})
@@ -146,3 +297,60 @@ func TestPrettyFormatter(t *testing.T) {
 		t.Errorf("Incorrect log suffix from output -> \nactual: %s", got)
 	}
 }
+
+func TestCommonLogFormatter(t *testing.T) {
+	e := &Event{
+		Time:    time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*3600)),
+		Level:   LevelInfo,
+		Message: "request",
+		Fields: []Field{
+			F("remote_addr", "127.0.0.1"),
+			F("user", "frank"),
+			F("method", "GET"),
+			F("uri", "/apache_pb.gif"),
+			F("proto", "HTTP/1.0"),
+			F("status", 200),
+			F("bytes", int64(2326)),
+			F("referer", "http://www.example.com/start.html"),
+			F("user_agent", "Mozilla/4.08 \"quoted\""),
+		},
+	}
+
+	want := "127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] \"GET /apache_pb.gif HTTP/1.0\" 200 2326\n"
+	NewCommonLogFormatter().Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %sHave: %s", want, string(e.buf))
+	}
+
+	e.buf = e.buf[:0]
+	want = "127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] \"GET /apache_pb.gif HTTP/1.0\" 200 2326 " +
+		"\"http://www.example.com/start.html\" \"Mozilla/4.08 \\\"quoted\\\"\"\n"
+	NewCombinedLogFormatter().Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %sHave: %s", want, string(e.buf))
+	}
+}
+
+func TestCommonLogFormatter_missing(t *testing.T) {
+	e := &Event{
+		Time:    time.Date(2000, 10, 10, 13, 55, 36, 0, time.UTC),
+		Level:   LevelInfo,
+		Message: "server started",
+	}
+
+	want := "- - - [10/Oct/2000:13:55:36 +0000] \"server started\" - -\n"
+	NewCommonLogFormatter().Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %sHave: %s", want, string(e.buf))
+	}
+
+	// lines of a handler carry the method of the request but are not access lines
+	e.buf = e.buf[:0]
+	e.Message = "mandate signed"
+	e.Fields = []Field{F("method", "GET"), F("path", "/mandates")}
+	want = "- - - [10/Oct/2000:13:55:36 +0000] \"mandate signed\" - -\n"
+	NewCommonLogFormatter().Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %sHave: %s", want, string(e.buf))
+	}
+}
@@ -0,0 +1,80 @@
+package httplog
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/twikey/go-logger"
+)
+
+// Field names of an access log line, which match the defaults of logger.CommonLogFormatter.
+const (
+	FieldRemoteAddr = "remote_addr"
+	FieldUser       = "user"
+	FieldMethod     = "method"
+	FieldURI        = "uri"
+	FieldPath       = "path"
+	FieldProto      = "proto"
+	FieldStatus     = "status"
+	FieldBytes      = "bytes"
+	FieldDuration   = "duration"
+	FieldReferer    = "referer"
+	FieldUserAgent  = "user_agent"
+)
+
+// AccessLog returns middleware that logs every request once it is done, with the method, path, status,
+// bytes, duration, remote address and user agent as fields. Server errors are logged at LevelError,
+// client errors at LevelWarning and all other requests at LevelInfo.
+//
+// The handler receives a request-scoped logger through logger.FromContext that carries the method and path.
+// Use logger.NewCommonLogFormatter or logger.NewCombinedLogFormatter to write Apache style access logs.
+func AccessLog(l *logger.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := l.Clock().Now()
+			rw := WrapResponseWriter(w)
+			base := requestLogger(l, r)
+			scoped := base.With(logger.F(FieldMethod, r.Method), logger.F(FieldPath, r.URL.Path))
+
+			defer func() {
+				err := recover()
+				status := rw.Status()
+				if err != nil {
+					status = http.StatusInternalServerError
+				}
+
+				fields := []logger.Field{
+					logger.F(FieldRemoteAddr, remoteHost(r)),
+					logger.F(FieldMethod, r.Method),
+					logger.F(FieldPath, r.URL.Path),
+					logger.F(FieldURI, r.RequestURI),
+					logger.F(FieldProto, r.Proto),
+					logger.F(FieldStatus, status),
+					logger.F(FieldBytes, rw.BytesWritten()),
+					logger.F(FieldDuration, l.Clock().Now().Sub(start)),
+					logger.F(FieldReferer, r.Referer()),
+					logger.F(FieldUserAgent, r.UserAgent()),
+				}
+				if user, _, ok := r.BasicAuth(); ok {
+					fields = append(fields, logger.F(FieldUser, user))
+				}
+				base.With(fields...).Log(levelForStatus(status), "request")
+
+				if err != nil {
+					panic(err)
+				}
+			}()
+
+			next.ServeHTTP(rw, r.WithContext(logger.NewContext(r.Context(), scoped)))
+		})
+	}
+}
+
+// remoteHost returns the host of the remote address without the port.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
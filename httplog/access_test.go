@@ -0,0 +1,55 @@
+package httplog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/twikey/go-logger"
+)
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf, Formatter: logger.NewCombinedLogFormatter()})
+
+	handler := AccessLog(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if logger.FromContext(r.Context()) == nil {
+			t.Errorf("expected request-scoped logger")
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/mandates?id=42", nil)
+	r.Header.Set("User-Agent", "test")
+	r.SetBasicAuth("frank", "secret")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "192.0.2.1 - frank [") {
+		t.Errorf("unexpected access log prefix: %s", got)
+	}
+	if !strings.HasSuffix(got, "] \"GET /mandates?id=42 HTTP/1.1\" 200 5 \"-\" \"test\"\n") {
+		t.Errorf("unexpected access log suffix: %s", got)
+	}
+}
+
+func TestAccessLog_level(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf})
+
+	handler := AccessLog(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.FromContext(r.Context()).Info("handling")
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	got := buf.String()
+	if !strings.Contains(got, "lvl=info msg=handling method=POST path=/\n") {
+		t.Errorf("expected request-scoped line with method and path, got: %s", got)
+	}
+	if !strings.Contains(got, "lvl=error msg=request") || !strings.Contains(got, "status=502") {
+		t.Errorf("expected access line at error level, got: %s", got)
+	}
+}
@@ -0,0 +1,246 @@
+package httplog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+// redacted replaces the values of redacted headers and query parameters.
+const redacted = "REDACTED"
+
+// Field names of an outbound request line, next to the method, status and duration fields.
+const (
+	FieldURL             = "url"
+	FieldAttempt         = "attempt"
+	FieldError           = "error"
+	FieldRequestHeaders  = "request_headers"
+	FieldResponseHeaders = "response_headers"
+	FieldBody            = "body"
+)
+
+// TransportOptions configures the http.RoundTripper returned by Transport.
+type TransportOptions struct {
+	// Base is the transport used to send the requests. Defaults to http.DefaultTransport.
+	Base http.RoundTripper
+
+	// RedactHeaders are the headers of which the values are never logged.
+	// Defaults to Authorization, Proxy-Authorization, Cookie and Set-Cookie.
+	RedactHeaders []string
+
+	// RedactQuery are the query parameters of which the values are never logged.
+	RedactQuery []string
+
+	// LogBodies logs the headers and bodies of requests and responses at trace level.
+	LogBodies bool
+
+	// MaxBodySize truncates the logged bodies. Defaults to 1KiB.
+	MaxBodySize int
+
+	// SlowThreshold logs responses that take longer than the threshold at warning level.
+	SlowThreshold time.Duration
+
+	// MaxRetries is the number of times a request is retried when the transport returns an error.
+	// Only requests with an idempotent method and a body that can be recreated are retried, and retrying stops
+	// when the context of the request is done.
+	MaxRetries int
+
+	// RetryBackoff is the wait before the first retry, which doubles for every next retry. Defaults to 100ms.
+	RetryBackoff time.Duration
+}
+
+// transport logs every outbound request through the logger.
+type transport struct {
+	l       *logger.Logger
+	opts    TransportOptions
+	headers map[string]bool
+	query   map[string]bool
+}
+
+// Transport returns a http.RoundTripper that logs the method, URL, status, duration and attempt of every outbound
+// request through the logger. Transport errors and server errors are logged at LevelError, slow responses at
+// LevelWarning and all other responses at LevelInfo. Sensitive headers and query parameters are redacted.
+func Transport(l *logger.Logger, opts TransportOptions) http.RoundTripper {
+	if opts.Base == nil {
+		opts.Base = http.DefaultTransport
+	}
+	if opts.RedactHeaders == nil {
+		opts.RedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = 1 << 10
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 100 * time.Millisecond
+	}
+
+	t := &transport{
+		l:       l,
+		opts:    opts,
+		headers: make(map[string]bool),
+		query:   make(map[string]bool),
+	}
+	for _, h := range opts.RedactHeaders {
+		t.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, q := range opts.RedactQuery {
+		t.query[q] = true
+	}
+	return t
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	l := t.l.WithContext(r.Context()).With(logger.F(FieldMethod, r.Method), logger.F(FieldURL, t.redactURL(r.URL)))
+
+	for attempt := 1; ; attempt++ {
+		res, err := t.attempt(l.With(logger.F(FieldAttempt, attempt)), r)
+		if err == nil || attempt > t.opts.MaxRetries || !retryable(r) {
+			return res, err
+		}
+		if !wait(r.Context(), t.opts.RetryBackoff<<(attempt-1)) {
+			return res, err // context is done
+		}
+
+		if r.GetBody != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = r.Clone(r.Context())
+			r.Body = body
+		}
+	}
+}
+
+// attempt sends the request once and logs the outcome.
+func (t *transport) attempt(l *logger.Logger, r *http.Request) (*http.Response, error) {
+	logBodies := t.opts.LogBodies && l.Enabled(logger.LevelTrace)
+	if logBodies {
+		fields := []logger.Field{logger.F(FieldRequestHeaders, t.redactHeaders(r.Header))}
+		if r.Body != nil && r.Body != http.NoBody {
+			var body []byte
+			r = r.Clone(r.Context()) // a RoundTripper must not modify the request
+			body, r.Body = t.peek(r.Body)
+			fields = append(fields, logger.F(FieldBody, body))
+		}
+		l.With(fields...).Trace("http request")
+	}
+
+	clock := l.Clock()
+	start := clock.Now()
+	res, err := t.opts.Base.RoundTrip(r)
+	duration := clock.Now().Sub(start)
+
+	if err != nil {
+		l.With(logger.F(FieldDuration, duration), logger.F(FieldError, err)).Error("http request failed")
+		return res, err
+	}
+
+	lvl := logger.LevelInfo
+	switch {
+	case res.StatusCode >= http.StatusInternalServerError:
+		lvl = logger.LevelError
+	case t.opts.SlowThreshold > 0 && duration > t.opts.SlowThreshold:
+		lvl = logger.LevelWarning
+	}
+	l.With(logger.F(FieldStatus, res.StatusCode), logger.F(FieldDuration, duration)).Log(lvl, "http request")
+
+	if logBodies {
+		fields := []logger.Field{logger.F(FieldStatus, res.StatusCode), logger.F(FieldResponseHeaders, t.redactHeaders(res.Header))}
+		if res.Body != nil && res.Body != http.NoBody {
+			var body []byte
+			body, res.Body = t.peek(res.Body)
+			fields = append(fields, logger.F(FieldBody, body))
+		}
+		l.With(fields...).Trace("http response")
+	}
+	return res, nil
+}
+
+// peek reads up to the maximum body size and returns a body that still yields the complete content.
+func (t *transport) peek(body io.ReadCloser) ([]byte, io.ReadCloser) {
+	prefix, _ := io.ReadAll(io.LimitReader(body, int64(t.opts.MaxBodySize)+1))
+	rest := readCloser{Reader: io.MultiReader(bytes.NewReader(prefix), body), Closer: body}
+
+	logged := prefix
+	if len(logged) > t.opts.MaxBodySize {
+		logged = append(logged[:t.opts.MaxBodySize:t.opts.MaxBodySize], "...(truncated)"...)
+	}
+	return logged, rest
+}
+
+// readCloser combines a reader with the closer of the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// redactURL returns the URL without password and with the values of redacted query parameters replaced.
+func (t *transport) redactURL(u *url.URL) string {
+	if len(t.query) > 0 && u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if t.query[key] {
+				query[key] = []string{redacted}
+			}
+		}
+		clone := *u
+		clone.RawQuery = query.Encode()
+		u = &clone
+	}
+	return u.Redacted()
+}
+
+// redactHeaders returns the headers sorted by key as a single line with the values of redacted headers replaced.
+func (t *transport) redactHeaders(h http.Header) string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		values := h[key]
+		if b.Len() > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(key)
+		b.WriteString(": ")
+		if t.headers[http.CanonicalHeaderKey(key)] {
+			b.WriteString(redacted)
+		} else {
+			b.WriteString(strings.Join(values, ", "))
+		}
+	}
+	return b.String()
+}
+
+// wait waits for the duration and returns false when the context is done first.
+func wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// retryable returns true when the request can safely be sent again.
+func retryable(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return r.Body == nil || r.Body == http.NoBody || r.GetBody != nil
+	default:
+		return false
+	}
+}
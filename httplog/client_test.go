@@ -0,0 +1,182 @@
+package httplog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf, Level: logger.LevelTrace})
+	client := &http.Client{Transport: Transport(l, TransportOptions{
+		RedactQuery: []string{"token"},
+		LogBodies:   true,
+		MaxBodySize: 5,
+	})}
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/collect?token=secret&id=42", strings.NewReader("hello world"))
+	req.Header.Set("Authorization", "Bearer secret")
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+
+	if string(body) != "hello world" {
+		t.Errorf("expected complete body to be sent and received, got: %s", body)
+	}
+
+	got := buf.String()
+	if strings.Contains(got, "secret") {
+		t.Errorf("expected secrets to be redacted, got: %s", got)
+	}
+	for _, want := range []string{
+		"msg=\"http request\" method=POST url=\"" + server.URL + "/collect?id=42&token=REDACTED\" attempt=1 request_headers=",
+		"Authorization: REDACTED",
+		"body=hello...(truncated)",
+		"status=200 duration=",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output: %s", want, got)
+		}
+	}
+}
+
+func TestTransport_levels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	var tests = []struct {
+		path string
+		want string
+	}{
+		{"/", "lvl=info"},
+		{"/slow", "lvl=warn"},
+		{"/error", "lvl=error"},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		l := logger.NewWithOptions(logger.Options{Writer: &buf})
+		client := &http.Client{Transport: Transport(l, TransportOptions{SlowThreshold: 50 * time.Millisecond})}
+
+		res, err := client.Get(server.URL + test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = res.Body.Close()
+
+		if !strings.Contains(buf.String(), test.want) {
+			t.Errorf("expected %s for %s, got: %s", test.want, test.path, buf.String())
+		}
+	}
+}
+
+func TestTransport_retries(t *testing.T) {
+	var attempts int
+	base := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errors.New("connection reset")
+	})
+
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf})
+	client := &http.Client{Transport: Transport(l, TransportOptions{Base: base, MaxRetries: 2, RetryBackoff: time.Millisecond})}
+
+	if _, err := client.Get("http://bank.example/mandates"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	got := buf.String()
+	if attempts != 3 || strings.Count(got, "lvl=error") != 3 || !strings.Contains(got, "attempt=3") {
+		t.Errorf("expected 3 logged attempts, got %d: %s", attempts, got)
+	}
+}
+
+func TestTransport_retriesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts int
+	base := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		cancel()
+		return nil, errors.New("connection reset")
+	})
+
+	l := logger.NewWithOptions(logger.Options{Writer: io.Discard})
+	transport := Transport(l, TransportOptions{Base: base, MaxRetries: 5, RetryBackoff: time.Hour})
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://bank.example/mandates", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Errorf("expected no retries after the context is done, got %d attempts", attempts)
+	}
+}
+
+func TestTransport_request(t *testing.T) {
+	base := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}}, nil
+	})
+
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf, Level: logger.LevelTrace})
+	transport := Transport(l, TransportOptions{Base: base, LogBodies: true})
+
+	body := io.NopCloser(strings.NewReader("hello"))
+	req, _ := http.NewRequest(http.MethodPost, "http://bank.example/mandates", body)
+	req.Header.Set("X-Request-ID", "42")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Body != body {
+		t.Errorf("expected the body of the request not to be replaced")
+	}
+	want := `request_headers="Accept: application/json; Content-Type: application/json; X-Request-Id: 42"`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("expected sorted headers %s, got: %s", want, buf.String())
+	}
+}
+
+func TestTransport_clock(t *testing.T) {
+	base := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf, Clock: logger.NewSteppingClock(logger.DeterministicTime, 250*time.Millisecond)})
+	req, _ := http.NewRequest(http.MethodGet, "http://bank.example/mandates", nil)
+	if _, err := Transport(l, TransportOptions{Base: base}).RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "duration=250ms") {
+		t.Errorf("expected the duration to be measured with the clock of the logger, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,83 @@
+// Package httplog provides net/http middleware that integrates the logger with HTTP servers.
+package httplog
+
+import (
+	"net/http"
+
+	"github.com/twikey/go-logger"
+)
+
+// Middleware wraps a http.Handler with additional behaviour.
+type Middleware func(next http.Handler) http.Handler
+
+// FingersCrossed returns middleware that gives every request a buffered logger, available through
+// logger.FromContext. The held events of a request are all flushed when an event at the trigger level
+// is logged, the handler panics or the response status is 500 or above. Successful requests only flush
+// the events at or above the SuccessLevel of the options.
+func FingersCrossed(l *logger.Logger, opts logger.BufferOptions) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			buffered, buf := requestLogger(l, r).Buffered(opts)
+			rw := WrapResponseWriter(w)
+
+			defer func() {
+				if err := recover(); err != nil {
+					buf.Close(true)
+					panic(err)
+				}
+				buf.Close(rw.Status() >= http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(rw, r.WithContext(logger.NewContext(r.Context(), buffered)))
+		})
+	}
+}
+
+// Canonical returns middleware that accumulates fields during a request and logs them as a single canonical
+// line once the request is done. Handlers add fields using logger.AddCanonical with the request context.
+// The line carries the method, path, status, bytes and duration of the request next to the added fields.
+func Canonical(l *logger.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := l.Clock().Now()
+			c := logger.NewCanonical()
+			c.Add(
+				logger.F(FieldMethod, r.Method),
+				logger.F(FieldPath, r.URL.Path),
+			)
+			rw := WrapResponseWriter(w)
+
+			defer func() {
+				err := recover()
+				status := rw.Status()
+				if err != nil {
+					status = http.StatusInternalServerError
+					c.Add(logger.F("panic", err))
+				}
+				c.Add(
+					logger.F(FieldStatus, status),
+					logger.F(FieldBytes, rw.BytesWritten()),
+					logger.F(FieldDuration, l.Clock().Now().Sub(start)),
+				)
+				c.Log(requestLogger(l, r), levelForStatus(status), "canonical-log-line")
+				if err != nil {
+					panic(err)
+				}
+			}()
+
+			next.ServeHTTP(rw, r.WithContext(logger.WithCanonical(r.Context(), c)))
+		})
+	}
+}
+
+// levelForStatus returns the level to log a request with the status code at.
+func levelForStatus(status int) logger.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return logger.LevelError
+	case status >= http.StatusBadRequest:
+		return logger.LevelWarning
+	default:
+		return logger.LevelInfo
+	}
+}
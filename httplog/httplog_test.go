@@ -0,0 +1,86 @@
+package httplog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/twikey/go-logger"
+)
+
+func TestFingersCrossed(t *testing.T) {
+	var tests = []struct {
+		name   string
+		status int
+		want   string
+	}{
+		{
+			"success flushes success level",
+			http.StatusOK,
+			"info - handling request\n",
+		},
+		{
+			"failure flushes everything",
+			http.StatusInternalServerError,
+			"debug - loading mandate\ninfo - handling request\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := logger.NewWithOptions(logger.Options{Writer: &buf, Formatter: logger.NewJournalFormatter(), Level: logger.LevelInfo})
+
+			handler := FingersCrossed(l, logger.BufferOptions{SuccessLevel: logger.LevelInfo})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				log := logger.FromContext(r.Context())
+				log.Debug("loading mandate")
+				log.Info("handling request")
+				w.WriteHeader(test.status)
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if got := buf.String(); got != test.want {
+				t.Errorf("\nWant: %s\nGot: %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestWrapResponseWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := WrapResponseWriter(rec)
+
+	if _, ok := rw.(http.Flusher); !ok {
+		t.Errorf("expected http.Flusher to be preserved")
+	}
+	if _, ok := rw.(http.Hijacker); ok {
+		t.Errorf("expected http.Hijacker to not be added")
+	}
+
+	_, _ = rw.Write([]byte("hello"))
+	if rw.Status() != http.StatusOK || rw.BytesWritten() != 5 {
+		t.Errorf("unexpected status %d or bytes %d", rw.Status(), rw.BytesWritten())
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf})
+
+	handler := Canonical(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.AddCanonical(r.Context(), logger.F("mandate", 42))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/mandates/42", nil))
+
+	got := buf.String()
+	for _, want := range []string{"lvl=warn", "msg=canonical-log-line", "method=GET", "path=/mandates/42", "mandate=42", "status=404", "bytes=0", "duration="} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in canonical line: %s", want, got)
+		}
+	}
+}
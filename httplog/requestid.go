@@ -0,0 +1,172 @@
+package httplog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+const (
+	// HeaderRequestID is the header used to read, echo and propagate the request ID.
+	HeaderRequestID = "X-Request-ID"
+
+	// HeaderTraceParent is the W3C trace context header, its trace ID is used when no request ID is present.
+	HeaderTraceParent = "traceparent"
+
+	// FieldRequestID is the field name of the request ID.
+	FieldRequestID = "request_id"
+
+	// maxRequestIDLength is the maximum length of a request ID received from a client.
+	maxRequestIDLength = 128
+)
+
+// crockford is the Crockford base32 alphabet used to encode request IDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// requestIDGenerator generates ULID-like identifiers that are monotonic within the same millisecond.
+type requestIDGenerator struct {
+	mu      sync.Mutex
+	last    uint64   // last millisecond timestamp
+	entropy [10]byte // 80 bits of randomness
+}
+
+var generator requestIDGenerator
+
+// NewRequestID returns a new ULID-like request ID: a 48-bit millisecond timestamp followed by 80 bits of
+// randomness, encoded as 26 characters of Crockford base32. IDs sort by their creation time and IDs that are
+// generated within the same millisecond are monotonically increasing.
+func NewRequestID() string {
+	return generator.next(time.Now())
+}
+
+func (g *requestIDGenerator) next(now time.Time) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(now.UnixMilli())
+	if ms <= g.last {
+		// same millisecond or clock went backwards -> increment the entropy
+		ms = g.last
+		for i := len(g.entropy) - 1; i >= 0; i-- {
+			g.entropy[i]++
+			if g.entropy[i] != 0 {
+				break
+			}
+		}
+	} else {
+		if _, err := rand.Read(g.entropy[:]); err != nil {
+			panic("httplog: unable to read random bytes: " + err.Error())
+		}
+		g.last = ms
+	}
+
+	var id [16]byte
+	binary.BigEndian.PutUint16(id[0:], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:], uint32(ms))
+	copy(id[6:], g.entropy[:])
+	return encodeCrockford(id)
+}
+
+// encodeCrockford encodes the 128-bit id as 26 characters, the first character holds the 3 most significant bits.
+func encodeCrockford(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[0:])
+	lo := binary.BigEndian.Uint64(id[8:])
+
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// requestIDKey is the key used to store the request ID in a context.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of the context that carries the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by the context, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID returns middleware that reads the request ID from the X-Request-ID header, falls back to the
+// trace ID of the traceparent header or generates a new ID. The ID is carried by the request context,
+// echoed in the response header and attached to the request-scoped logger available through logger.FromContext.
+// Place it before the other middleware of this package so their lines carry the request ID as well.
+func RequestID(l *logger.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := requestID(r)
+			w.Header().Set(HeaderRequestID, id)
+
+			r = r.WithContext(WithRequestID(r.Context(), id))
+			next.ServeHTTP(w, r.WithContext(logger.NewContext(r.Context(), requestLogger(l, r))))
+		})
+	}
+}
+
+// requestID returns the request ID of the incoming request, or a new ID when none is present or valid.
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(HeaderRequestID); validRequestID(id) {
+		return id
+	}
+	if sc, err := logger.ParseTraceParent(r.Header.Get(HeaderTraceParent)); err == nil {
+		return sc.TraceID.String()
+	}
+	return NewRequestID()
+}
+
+// validRequestID returns true when the id is not empty, not too long and only contains printable ASCII.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestLogger binds the logger to the request context and attaches the request ID carried by the context.
+func requestLogger(l *logger.Logger, r *http.Request) *logger.Logger {
+	l = l.WithContext(r.Context())
+	if id := RequestIDFromContext(r.Context()); id != "" {
+		return l.With(logger.F(FieldRequestID, id))
+	}
+	return l
+}
+
+// RequestIDTransport returns a http.RoundTripper that forwards the request ID carried by the request context
+// to outbound requests in the X-Request-ID header. When base is nil http.DefaultTransport is used.
+func RequestIDTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if id := RequestIDFromContext(r.Context()); id != "" && r.Header.Get(HeaderRequestID) == "" {
+			r = r.Clone(r.Context())
+			r.Header.Set(HeaderRequestID, id)
+		}
+		return base.RoundTrip(r)
+	})
+}
+
+// roundTripperFunc is an adapter to allow the use of ordinary functions as http.RoundTripper.
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,123 @@
+package httplog
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+func TestNewRequestID(t *testing.T) {
+	var g requestIDGenerator
+	now := time.UnixMilli(1729066279358)
+
+	first := g.next(now)
+	second := g.next(now)
+	later := g.next(now.Add(time.Millisecond))
+
+	if len(first) != 26 {
+		t.Errorf("expected 26 characters but got %d: %s", len(first), first)
+	}
+	if !(first < second && second < later) {
+		t.Errorf("expected monotonic ids: %s, %s, %s", first, second, later)
+	}
+	if first[:10] != second[:10] {
+		t.Errorf("expected same timestamp prefix: %s, %s", first, second)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var tests = []struct {
+		name   string
+		header map[string]string
+		want   string
+	}{
+		{
+			"request id header",
+			map[string]string{HeaderRequestID: "abc-123"},
+			"abc-123",
+		},
+		{
+			"traceparent header",
+			map[string]string{HeaderTraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			"4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			"invalid request id header",
+			map[string]string{HeaderRequestID: "forged\nline"},
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := logger.NewWithOptions(logger.Options{Writer: &buf})
+
+			var id string
+			handler := RequestID(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id = RequestIDFromContext(r.Context())
+				logger.FromContext(r.Context()).Info("handling")
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range test.header {
+				r.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+
+			if test.want != "" && id != test.want {
+				t.Errorf("expected request id %s but got %s", test.want, id)
+			}
+			if len(id) == 0 || rec.Header().Get(HeaderRequestID) != id {
+				t.Errorf("expected request id %q to be echoed, got %q", id, rec.Header().Get(HeaderRequestID))
+			}
+			if !strings.Contains(buf.String(), "request_id="+id) {
+				t.Errorf("expected request id on log line, got: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestRequestIDTransport(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(HeaderRequestID)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: RequestIDTransport(nil)}
+	r, _ := http.NewRequestWithContext(WithRequestID(context.Background(), "abc-123"), http.MethodGet, server.URL, nil)
+	res, err := client.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+
+	if got != "abc-123" {
+		t.Errorf("expected request id to be forwarded, got %q", got)
+	}
+	if r.Header.Get(HeaderRequestID) != "" {
+		t.Errorf("expected original request to be left untouched")
+	}
+}
+
+func TestAccessLog_requestID(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf})
+
+	handler := RequestID(l)(AccessLog(l)(http.NotFoundHandler()))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(HeaderRequestID, "abc-123")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if !strings.Contains(buf.String(), "request_id=abc-123") {
+		t.Errorf("expected request id on access line, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,114 @@
+package httplog
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter wraps a http.ResponseWriter to capture the status code and the number of bytes written.
+type ResponseWriter interface {
+	http.ResponseWriter
+
+	// Status returns the status code of the response, or http.StatusOK when nothing was written yet.
+	Status() int
+
+	// BytesWritten returns the number of bytes written to the response body.
+	BytesWritten() int64
+}
+
+// responseWriter is the default ResponseWriter implementation.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *responseWriter) BytesWritten() int64 {
+	return w.bytes
+}
+
+// Unwrap allows http.ResponseController to access the underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// flushWriter preserves the http.Flusher interface of the underlying writer.
+type flushWriter struct {
+	*responseWriter
+}
+
+func (w flushWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+// hijackWriter preserves the http.Hijacker interface of the underlying writer.
+type hijackWriter struct {
+	*responseWriter
+}
+
+func (w hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// flushHijackWriter preserves both the http.Flusher and http.Hijacker interfaces of the underlying writer.
+type flushHijackWriter struct {
+	*responseWriter
+}
+
+func (w flushHijackWriter) Flush() {
+	flushWriter(w).Flush()
+}
+
+func (w flushHijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return hijackWriter(w).Hijack()
+}
+
+// WrapResponseWriter wraps the writer to capture the status code and the number of bytes written.
+// The optional http.Flusher and http.Hijacker interfaces are preserved. Writers that already implement
+// ResponseWriter are returned as is.
+func WrapResponseWriter(w http.ResponseWriter) ResponseWriter {
+	if rw, ok := w.(ResponseWriter); ok {
+		return rw
+	}
+
+	rw := &responseWriter{ResponseWriter: w}
+	_, flusher := w.(http.Flusher)
+	_, hijacker := w.(http.Hijacker)
+	switch {
+	case flusher && hijacker:
+		return flushHijackWriter{rw}
+	case flusher:
+		return flushWriter{rw}
+	case hijacker:
+		return hijackWriter{rw}
+	default:
+		return rw
+	}
+}
@@ -0,0 +1,51 @@
+package httplog
+
+import (
+	"net/http"
+
+	"github.com/twikey/go-logger"
+)
+
+// HeaderTraceState is the W3C trace context header carrying vendor specific trace information.
+const HeaderTraceState = "tracestate"
+
+// Trace returns middleware that extracts the span context from the traceparent and tracestate headers,
+// or starts a new trace when the headers are missing or invalid. The request is handled in a child span that is
+// carried by the request context, so every line of the request-scoped logger available through logger.FromContext
+// carries the trace_id, span_id and trace_flags fields. Use TraceTransport to inject the headers in outbound calls.
+func Trace(l *logger.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sc, err := logger.ParseTraceParent(r.Header.Get(HeaderTraceParent))
+			if err != nil {
+				sc = logger.NewSpanContext(0)
+			} else {
+				sc = sc.Child()
+				if state, err := logger.ParseTraceState(r.Header.Get(HeaderTraceState)); err == nil {
+					sc.State = state
+				}
+			}
+
+			r = r.WithContext(logger.ContextWithSpan(r.Context(), sc))
+			next.ServeHTTP(w, r.WithContext(logger.NewContext(r.Context(), requestLogger(l, r))))
+		})
+	}
+}
+
+// TraceTransport returns a http.RoundTripper that injects the traceparent and tracestate headers for the span
+// carried by the request context in outbound requests. When base is nil http.DefaultTransport is used.
+func TraceTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if sc, ok := logger.SpanFromContext(r.Context()); ok && r.Header.Get(HeaderTraceParent) == "" {
+			r = r.Clone(r.Context())
+			r.Header.Set(HeaderTraceParent, sc.Child().TraceParent())
+			if len(sc.State) > 0 {
+				r.Header.Set(HeaderTraceState, sc.State.String())
+			}
+		}
+		return base.RoundTrip(r)
+	})
+}
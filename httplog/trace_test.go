@@ -0,0 +1,69 @@
+package httplog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/twikey/go-logger"
+)
+
+func TestTrace(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf})
+
+	var outbound http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outbound = r.Header
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: TraceTransport(nil)}
+
+	handler := Trace(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.FromContext(r.Context()).Info("handling")
+
+		req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, server.URL, nil)
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = res.Body.Close()
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.Header.Set(HeaderTraceState, "rojo=00f067aa0ba902b7")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	got := buf.String()
+	if !strings.Contains(got, "trace_id=4bf92f3577b34da6a3ce929d0e0e4736") || strings.Contains(got, "span_id=00f067aa0ba902b7") {
+		t.Errorf("expected child span fields on log line, got: %s", got)
+	}
+
+	sc, err := logger.ParseTraceParent(outbound.Get(HeaderTraceParent))
+	if err != nil || sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected traceparent to be injected, got: %q", outbound.Get(HeaderTraceParent))
+	}
+	if outbound.Get(HeaderTraceState) != "rojo=00f067aa0ba902b7" {
+		t.Errorf("expected tracestate to be injected, got: %q", outbound.Get(HeaderTraceState))
+	}
+}
+
+func TestTrace_newTrace(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.NewWithOptions(logger.Options{Writer: &buf})
+
+	handler := Trace(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := logger.SpanFromContext(r.Context()); !ok {
+			t.Errorf("expected new span in context")
+		}
+		logger.FromContext(r.Context()).Info("handling")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !strings.Contains(buf.String(), "trace_flags=00") {
+		t.Errorf("expected trace fields for new trace, got: %s", buf.String())
+	}
+}
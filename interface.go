@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+)
+
+// Interface declares the logging methods of a Logger, which allows mocking or decorating the logger.
+// Use Logger.Interface to satisfy it with a Logger, or Nop to discard every line.
+type Interface interface {
+	Panic(message string, args ...interface{})
+	Panicf(format string, a ...interface{})
+	Fatal(message string, args ...interface{})
+	Fatalf(format string, a ...interface{})
+	Error(message string, args ...interface{})
+	Errorf(format string, a ...interface{})
+	Warning(message string, args ...interface{})
+	Warningf(format string, a ...interface{})
+	Info(message string, args ...interface{})
+	Infof(format string, a ...interface{})
+	Debug(message string, args ...interface{})
+	Debugf(format string, a ...interface{})
+	Trace(message string, args ...interface{})
+	Tracef(format string, a ...interface{})
+
+	With(fields ...Field) Interface
+	WithName(name string) Interface
+}
+
+// Interface returns the logger as an Interface.
+func (l *Logger) Interface() Interface {
+	return loggerInterface{l}
+}
+
+// loggerInterface adapts the cloning methods of a Logger to return an Interface.
+type loggerInterface struct {
+	*Logger
+}
+
+func (l loggerInterface) With(fields ...Field) Interface {
+	return loggerInterface{l.Logger.With(fields...)}
+}
+
+func (l loggerInterface) WithName(name string) Interface {
+	return loggerInterface{l.Logger.WithName(name)}
+}
+
+// Nop is an Interface that discards every line without allocating.
+// Like a Logger, Fatal exits the application and Panic panics.
+type Nop struct{}
+
+var _ Interface = Nop{}
+
+func (Nop) Panic(message string, args ...interface{}) {
+	if len(args) > 0 {
+		message = renderTemplate(message, args)
+	}
+	panic(message)
+}
+
+func (Nop) Panicf(format string, a ...interface{}) {
+	panic(fmt.Sprintf(format, a...))
+}
+
+func (Nop) Fatal(string, ...interface{}) {
+	os.Exit(1)
+}
+
+func (Nop) Fatalf(string, ...interface{}) {
+	os.Exit(1)
+}
+
+func (Nop) Error(string, ...interface{})    {}
+func (Nop) Errorf(string, ...interface{})   {}
+func (Nop) Warning(string, ...interface{})  {}
+func (Nop) Warningf(string, ...interface{}) {}
+func (Nop) Info(string, ...interface{})     {}
+func (Nop) Infof(string, ...interface{})    {}
+func (Nop) Debug(string, ...interface{})    {}
+func (Nop) Debugf(string, ...interface{})   {}
+func (Nop) Trace(string, ...interface{})    {}
+func (Nop) Tracef(string, ...interface{})   {}
+
+func (n Nop) With(...Field) Interface {
+	return n
+}
+
+func (n Nop) WithName(string) Interface {
+	return n
+}
+
+// PrintAdapter satisfies the logger shapes of libraries that log through Printf, Print and Println,
+// or through Errorf, Warnf, Warningf, Infof and Debugf.
+type PrintAdapter struct {
+	l   *Logger
+	lvl Level
+}
+
+// PrintAdapter returns an adapter that logs Printf, Print and Println calls at the given level.
+func (l *Logger) PrintAdapter(lvl Level) *PrintAdapter {
+	return &PrintAdapter{l: l, lvl: lvl}
+}
+
+// Printf logs a message at the level of the adapter.
+func (a *PrintAdapter) Printf(format string, v ...interface{}) {
+	a.l.log(a.lvl, fmt.Sprintf(format, v...), nil)
+}
+
+// Print logs a message at the level of the adapter.
+func (a *PrintAdapter) Print(v ...interface{}) {
+	a.l.log(a.lvl, fmt.Sprint(v...), nil)
+}
+
+// Println logs a message at the level of the adapter. The trailing newline is not logged.
+func (a *PrintAdapter) Println(v ...interface{}) {
+	msg := fmt.Sprintln(v...)
+	a.l.log(a.lvl, msg[:len(msg)-1], nil)
+}
+
+// Errorf logs a message at Error level.
+func (a *PrintAdapter) Errorf(format string, v ...interface{}) {
+	a.l.log(LevelError, fmt.Sprintf(format, v...), nil)
+}
+
+// Warnf logs a message at Warning level.
+func (a *PrintAdapter) Warnf(format string, v ...interface{}) {
+	a.l.log(LevelWarning, fmt.Sprintf(format, v...), nil)
+}
+
+// Warningf logs a message at Warning level.
+func (a *PrintAdapter) Warningf(format string, v ...interface{}) {
+	a.l.log(LevelWarning, fmt.Sprintf(format, v...), nil)
+}
+
+// Infof logs a message at Info level.
+func (a *PrintAdapter) Infof(format string, v ...interface{}) {
+	a.l.log(LevelInfo, fmt.Sprintf(format, v...), nil)
+}
+
+// Debugf logs a message at Debug level.
+func (a *PrintAdapter) Debugf(format string, v ...interface{}) {
+	a.l.log(LevelDebug, fmt.Sprintf(format, v...), nil)
+}
+
+// KeyValueAdapter satisfies the leveled logger shape of libraries that log a message followed by
+// alternating keys and values, such as Error(msg string, keysAndValues ...interface{}).
+type KeyValueAdapter struct {
+	l *Logger
+}
+
+// KeyValueAdapter returns an adapter for leveled loggers with alternating keys and values.
+func (l *Logger) KeyValueAdapter() *KeyValueAdapter {
+	clone := l.clone()
+	clone.Pos++ // called through the adapter
+	return &KeyValueAdapter{l: clone}
+}
+
+// Error logs a message at Error level.
+func (a *KeyValueAdapter) Error(msg string, keysAndValues ...interface{}) {
+	a.log(LevelError, msg, keysAndValues)
+}
+
+// Warn logs a message at Warning level.
+func (a *KeyValueAdapter) Warn(msg string, keysAndValues ...interface{}) {
+	a.log(LevelWarning, msg, keysAndValues)
+}
+
+// Info logs a message at Info level.
+func (a *KeyValueAdapter) Info(msg string, keysAndValues ...interface{}) {
+	a.log(LevelInfo, msg, keysAndValues)
+}
+
+// Debug logs a message at Debug level.
+func (a *KeyValueAdapter) Debug(msg string, keysAndValues ...interface{}) {
+	a.log(LevelDebug, msg, keysAndValues)
+}
+
+func (a *KeyValueAdapter) log(lvl Level, msg string, keysAndValues []interface{}) {
+	if !a.l.should(lvl) {
+		return
+	}
+	if len(keysAndValues) == 0 {
+		a.l.log(lvl, msg, nil)
+		return
+	}
+	a.l.With(keyValueFields(keysAndValues)...).log(lvl, msg, nil)
+}
+
+// keyValueFields converts alternating keys and values to fields. A missing value is logged as "<missing>".
+func keyValueFields(keysAndValues []interface{}) []Field {
+	fields := make([]Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		var value interface{} = "<missing>"
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		fields = append(fields, F(key, value))
+	}
+	return fields
+}
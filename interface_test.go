@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// printfLogger is the shape used by libraries that log through a single Printf method.
+type printfLogger interface {
+	Printf(format string, v ...interface{})
+}
+
+// stdLogger is the shape of libraries that accept a standard library like logger.
+type stdLogger interface {
+	Print(v ...interface{})
+	Printf(format string, v ...interface{})
+	Println(v ...interface{})
+}
+
+// leveledFormatLogger is the shape of libraries that log through formatted leveled methods.
+type leveledFormatLogger interface {
+	Errorf(format string, v ...interface{})
+	Warningf(format string, v ...interface{})
+	Infof(format string, v ...interface{})
+	Debugf(format string, v ...interface{})
+}
+
+// warnfLogger is the shape of libraries that use Warnf instead of Warningf.
+type warnfLogger interface {
+	Errorf(format string, v ...interface{})
+	Warnf(format string, v ...interface{})
+	Debugf(format string, v ...interface{})
+}
+
+// keyValueLogger is the shape of libraries that log messages with alternating keys and values.
+type keyValueLogger interface {
+	Error(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Debug(msg string, keysAndValues ...interface{})
+}
+
+var (
+	_ Interface           = (*Logger)(nil).Interface()
+	_ leveledFormatLogger = (*Logger)(nil)
+	_ printfLogger        = (*PrintAdapter)(nil)
+	_ stdLogger           = (*PrintAdapter)(nil)
+	_ leveledFormatLogger = (*PrintAdapter)(nil)
+	_ warnfLogger         = (*PrintAdapter)(nil)
+	_ keyValueLogger      = (*KeyValueAdapter)(nil)
+)
+
+func TestInterface(t *testing.T) {
+	var buf bytes.Buffer
+	var log Interface = NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter()}).Interface()
+
+	log.WithName("mandates").With(F("id", 42)).Info("signed")
+
+	want := "[mandates] info - signed id=42\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestNop(t *testing.T) {
+	var log Interface = Nop{}
+	allocs := testing.AllocsPerRun(100, func() {
+		log.With().WithName("nop").Info("discarded")
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations but got %f", allocs)
+	}
+}
+
+func TestPrintAdapter(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter()}).PrintAdapter(LevelInfo)
+
+	adapter.Printf("retrying in %ds", 5)
+	adapter.Println("connected", true)
+	adapter.Warnf("slow response")
+
+	want := "info - retrying in 5s\n" +
+		"info - connected true\n" +
+		"warn - slow response\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestPrintAdapter_source(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := NewWithOptions(Options{Writer: &buf, Formatter: &PrettyFormatter{AppendSource: true}}).PrintAdapter(LevelInfo)
+
+	_, _, line, _ := runtime.Caller(0)
+	adapter.Printf("retrying")
+	want := fmt.Sprintf("interface_test.go:%d\n", line+1)
+	if got := buf.String(); !strings.HasSuffix(got, want) {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestKeyValueAdapter(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter()}).KeyValueAdapter()
+
+	adapter.Error("request failed", "status", 502, "retry")
+
+	want := "error - request failed status=502 retry=<missing>\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
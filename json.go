@@ -0,0 +1,329 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// JSONFormatter is a performance focused formatter which prints out the log lines as JSON objects,
+// objects and arrays are nested.
+type JSONFormatter struct {
+	// field names
+	TimestampField string
+	LevelField     string
+	MessageField   string
+	NameField      string
+	TemplateField  string // written after the message when it was rendered of a template
+}
+
+// NewJSONFormatter creates a new instance of the JSONFormatter which outputs log lines as JSON objects.
+func NewJSONFormatter() *JSONFormatter {
+	return &JSONFormatter{
+		TimestampField: "ts",
+		LevelField:     "lvl",
+		MessageField:   "msg",
+		NameField:      "logger",
+		TemplateField:  "template",
+	}
+}
+
+func (j *JSONFormatter) Format(e *Event) {
+	enc := &e.json
+	enc.e = e
+	enc.comma = false
+
+	e.buf = append(e.buf, '{')
+	if j.TimestampField != "" {
+		enc.AddInt(j.TimestampField, e.Time.UnixMilli())
+	}
+	if j.NameField != "" && e.Module != "" {
+		// only write module when not empty
+		enc.AddString(j.NameField, e.Module)
+	}
+	if j.LevelField != "" {
+		enc.AddString(j.LevelField, e.Level.String())
+	}
+	if j.MessageField != "" {
+		enc.AddString(j.MessageField, e.Message)
+	}
+	if j.TemplateField != "" && e.Template != "" {
+		enc.AddString(j.TemplateField, e.Template)
+	}
+	for _, f := range e.Fields {
+		if f.Key != "" {
+			enc.AddValue(f.Key, f.Value)
+		}
+	}
+	e.buf = append(e.buf, '}', newline)
+}
+
+// jsonEncoder encodes objects and arrays as nested JSON.
+type jsonEncoder struct {
+	e     *Event
+	comma bool // a value was written in the current object or array
+}
+
+// key appends the key of an object field.
+func (enc *jsonEncoder) key(key string) {
+	enc.element()
+	enc.e.buf = appendJSONString(enc.e.buf, key)
+	enc.e.buf = append(enc.e.buf, colon)
+}
+
+// element separates the value from the previous one.
+func (enc *jsonEncoder) element() {
+	if enc.comma {
+		enc.e.buf = append(enc.e.buf, ',')
+	}
+	enc.comma = true
+}
+
+func (enc *jsonEncoder) object(value ObjectMarshaler) {
+	enc.e.buf = append(enc.e.buf, '{')
+	enc.comma = false
+	value.LogObject(enc)
+	enc.comma = true
+	enc.e.buf = append(enc.e.buf, '}')
+}
+
+func (enc *jsonEncoder) array(value ArrayMarshaler) {
+	enc.e.buf = append(enc.e.buf, '[')
+	enc.comma = false
+	value.LogArray(enc)
+	enc.comma = true
+	enc.e.buf = append(enc.e.buf, ']')
+}
+
+// value appends a value of any type.
+func (enc *jsonEncoder) value(value interface{}) {
+	e := enc.e
+	switch v := value.(type) {
+	case string:
+		e.buf = appendJSONString(e.buf, v)
+	case int:
+		e.buf = strconv.AppendInt(e.buf, int64(v), 10)
+	case int8:
+		e.buf = strconv.AppendInt(e.buf, int64(v), 10)
+	case int16:
+		e.buf = strconv.AppendInt(e.buf, int64(v), 10)
+	case int32:
+		e.buf = strconv.AppendInt(e.buf, int64(v), 10)
+	case int64:
+		e.buf = strconv.AppendInt(e.buf, v, 10)
+	case uint:
+		e.buf = strconv.AppendUint(e.buf, uint64(v), 10)
+	case uint8:
+		e.buf = strconv.AppendUint(e.buf, uint64(v), 10)
+	case uint16:
+		e.buf = strconv.AppendUint(e.buf, uint64(v), 10)
+	case uint32:
+		e.buf = strconv.AppendUint(e.buf, uint64(v), 10)
+	case uint64:
+		e.buf = strconv.AppendUint(e.buf, v, 10)
+	case float32:
+		e.buf = appendJSONFloat(e.buf, float64(v), 32)
+	case float64:
+		e.buf = appendJSONFloat(e.buf, v, 64)
+	case bool:
+		e.buf = strconv.AppendBool(e.buf, v)
+	case nil:
+		e.buf = append(e.buf, "null"...)
+	case ObjectMarshaler:
+		enc.object(v)
+	case ArrayMarshaler:
+		enc.array(v)
+	case json.Marshaler:
+		// compact the output, so an indented value can not span multiple lines
+		b, err := v.MarshalJSON()
+		if err == nil {
+			dst := bytes.NewBuffer(e.buf)
+			if err = json.Compact(dst, b); err == nil {
+				e.buf = dst.Bytes()
+			}
+		}
+		if err != nil {
+			enc.text(value)
+		}
+	default:
+		enc.text(value)
+	}
+}
+
+// text appends the textual representation of the value as a JSON string.
+func (enc *jsonEncoder) text(value interface{}) {
+	start := len(enc.e.buf)
+	enc.e.buf = appendValue(enc.e.buf, value)
+	enc.e.buf = jsonQuote(enc.e.buf, start)
+}
+
+func (enc *jsonEncoder) AddString(key, value string) {
+	enc.key(key)
+	enc.e.buf = appendJSONString(enc.e.buf, value)
+}
+
+func (enc *jsonEncoder) AddInt(key string, value int64) {
+	enc.key(key)
+	enc.e.buf = strconv.AppendInt(enc.e.buf, value, 10)
+}
+
+func (enc *jsonEncoder) AddUint(key string, value uint64) {
+	enc.key(key)
+	enc.e.buf = strconv.AppendUint(enc.e.buf, value, 10)
+}
+
+func (enc *jsonEncoder) AddFloat(key string, value float64) {
+	enc.key(key)
+	enc.e.buf = appendJSONFloat(enc.e.buf, value, 64)
+}
+
+func (enc *jsonEncoder) AddBool(key string, value bool) {
+	enc.key(key)
+	enc.e.buf = strconv.AppendBool(enc.e.buf, value)
+}
+
+func (enc *jsonEncoder) AddDuration(key string, value time.Duration) {
+	enc.key(key)
+	enc.e.buf = appendJSONString(enc.e.buf, value.String())
+}
+
+func (enc *jsonEncoder) AddTime(key string, value time.Time) {
+	enc.key(key)
+	enc.e.buf = append(enc.e.buf, quote)
+	enc.e.buf = value.AppendFormat(enc.e.buf, time.RFC3339Nano)
+	enc.e.buf = append(enc.e.buf, quote)
+}
+
+func (enc *jsonEncoder) AddObject(key string, value ObjectMarshaler) {
+	enc.key(key)
+	enc.object(value)
+}
+
+func (enc *jsonEncoder) AddArray(key string, value ArrayMarshaler) {
+	enc.key(key)
+	enc.array(value)
+}
+
+func (enc *jsonEncoder) AddValue(key string, value interface{}) {
+	enc.key(key)
+	enc.value(value)
+}
+
+func (enc *jsonEncoder) AppendString(value string) {
+	enc.element()
+	enc.e.buf = appendJSONString(enc.e.buf, value)
+}
+
+func (enc *jsonEncoder) AppendInt(value int64) {
+	enc.element()
+	enc.e.buf = strconv.AppendInt(enc.e.buf, value, 10)
+}
+
+func (enc *jsonEncoder) AppendUint(value uint64) {
+	enc.element()
+	enc.e.buf = strconv.AppendUint(enc.e.buf, value, 10)
+}
+
+func (enc *jsonEncoder) AppendFloat(value float64) {
+	enc.element()
+	enc.e.buf = appendJSONFloat(enc.e.buf, value, 64)
+}
+
+func (enc *jsonEncoder) AppendBool(value bool) {
+	enc.element()
+	enc.e.buf = strconv.AppendBool(enc.e.buf, value)
+}
+
+func (enc *jsonEncoder) AppendDuration(value time.Duration) {
+	enc.element()
+	enc.e.buf = appendJSONString(enc.e.buf, value.String())
+}
+
+func (enc *jsonEncoder) AppendTime(value time.Time) {
+	enc.element()
+	enc.e.buf = append(enc.e.buf, quote)
+	enc.e.buf = value.AppendFormat(enc.e.buf, time.RFC3339Nano)
+	enc.e.buf = append(enc.e.buf, quote)
+}
+
+func (enc *jsonEncoder) AppendObject(value ObjectMarshaler) {
+	enc.element()
+	enc.object(value)
+}
+
+func (enc *jsonEncoder) AppendArray(value ArrayMarshaler) {
+	enc.element()
+	enc.array(value)
+}
+
+func (enc *jsonEncoder) AppendValue(value interface{}) {
+	enc.element()
+	enc.value(value)
+}
+
+// appendJSONString appends the value as a quoted and escaped JSON string.
+func appendJSONString(dst []byte, value string) []byte {
+	start := len(dst)
+	dst = append(dst, value...)
+	return jsonQuote(dst, start)
+}
+
+// jsonQuote replaces the value appended from start by a quoted JSON string. Quotes and backslashes are escaped with
+// a backslash, control characters as \n, \r, \t or \u00XX and invalid UTF-8 as \ufffd.
+func jsonQuote(buf []byte, start int) []byte {
+	end := len(buf)
+	raw := buf[start:end] // stays valid when appending reallocates the buffer
+
+	buf = append(buf, quote)
+	safe := 0 // start of the bytes that are not yet appended
+	for i := 0; i < len(raw); {
+		if c := raw[i]; c < utf8.RuneSelf && c >= ' ' && c != quote && c != '\\' && c != 0x7f {
+			i++
+			continue // fast path -> printable ascii is appended in bulk
+		}
+
+		r, size := utf8.DecodeRune(raw[i:])
+		switch {
+		case r == quote || r == '\\':
+			buf = append(buf, raw[safe:i]...)
+			buf = append(buf, '\\', byte(r))
+		case isControl(r):
+			buf = append(buf, raw[safe:i]...)
+			buf = appendEscaped(buf, r, 0, false)
+		case r == '\u2028' || r == '\u2029':
+			// line separators are valid JSON but break JavaScript
+			buf = append(buf, raw[safe:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, raw[safe:i]...)
+			buf = append(buf, `\ufffd`...)
+		default:
+			i += size
+			continue // valid multibyte rune is appended in bulk
+		}
+		i += size
+		safe = i
+	}
+	buf = append(buf, raw[safe:]...)
+	buf = append(buf, quote)
+
+	// move the quoted value over the raw value
+	n := copy(buf[start:], buf[end:])
+	return buf[:start+n]
+}
+
+// appendJSONFloat appends the float as a JSON number, or as a string for NaN and infinities.
+func appendJSONFloat(dst []byte, value float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(value):
+		return append(dst, `"NaN"`...)
+	case math.IsInf(value, 1):
+		return append(dst, `"+Inf"`...)
+	case math.IsInf(value, -1):
+		return append(dst, `"-Inf"`...)
+	}
+	return strconv.AppendFloat(dst, value, 'g', -1, bitSize)
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"fmt"
+)
+
+// maxLogValueDepth limits how many times a LogValuer returning another LogValuer is resolved.
+const maxLogValueDepth = 100
+
+// LogValuer is implemented by field values that are expensive to compute. LogValue is only called when the event
+// is going to be written, and only once per event. A panic in LogValue is recovered and logged as an error value.
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+// LogValuerFunc adapts a function to a LogValuer.
+type LogValuerFunc func() interface{}
+
+// LogValue implements the LogValuer interface.
+func (f LogValuerFunc) LogValue() interface{} {
+	return f()
+}
+
+// Lazy creates a field of which the value is only computed when the event is going to be written.
+// Field values of the type func() interface{} and func() string are resolved lazily as well.
+func Lazy(key string, fn func() interface{}) Field {
+	return Field{Key: key, Value: LogValuerFunc(fn)}
+}
+
+// resolveEvent renders the message template of the event and resolves its lazy field values.
+func resolveEvent(e *Event) {
+	if len(e.args) > 0 {
+		applyTemplate(e)
+	}
+	resolveFields(e.Fields)
+}
+
+// resolveFields replaces the lazy field values by their resolved value.
+func resolveFields(fields []Field) {
+	for i := range fields {
+		switch fields[i].Value.(type) {
+		case LogValuer, func() interface{}, func() string:
+			fields[i].Value = resolve(fields[i].Value)
+		}
+	}
+}
+
+// resolve resolves a lazy value until it is no longer lazy.
+func resolve(v interface{}) interface{} {
+	for i := 0; i < maxLogValueDepth; i++ {
+		switch lv := v.(type) {
+		case LogValuer:
+			v = safeLogValue(lv.LogValue)
+		case func() interface{}:
+			v = safeLogValue(lv)
+		case func() string:
+			v = safeLogValue(func() interface{} { return lv() })
+		default:
+			return v
+		}
+	}
+	return fmt.Errorf("!LOGVALUE: exceeded %d nested log values", maxLogValueDepth)
+}
+
+// safeLogValue calls the function and returns an error value when it panics.
+func safeLogValue(fn func() interface{}) (v interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			v = fmt.Errorf("!PANIC: %v", r)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type testBatch struct {
+	calls *int
+}
+
+func (b testBatch) LogValue() interface{} {
+	*b.calls++
+	return "<pain.008/>"
+}
+
+func TestLogger_lazy(t *testing.T) {
+	var buf bytes.Buffer
+	calls := 0
+	log := NewWithOptions(Options{
+		Writer:    &buf,
+		Formatter: &TextFormatter{MessageField: "msg"},
+		Level:     LevelInfo,
+		Sampler:   NewFieldSampler("batch", time.Minute, 10, 0),
+	})
+
+	log.With(F("batch", testBatch{calls: &calls})).Debug("skipped")
+	if calls != 0 {
+		t.Errorf("\nWant: %d\nGot: %d", 0, calls)
+	}
+
+	log.With(F("batch", testBatch{calls: &calls})).Info("exported")
+	want := "msg=exported batch=<pain.008/>\n"
+	if buf.String() != want || calls != 1 {
+		t.Errorf("\nWant: %s (1 call)\nGot: %s (%d calls)", want, buf.String(), calls)
+	}
+}
+
+func TestLogger_lazyRecorded(t *testing.T) {
+	var buf bytes.Buffer
+	calls := 0
+	recorder := NewRecorder(10, LevelError)
+	log := NewWithOptions(Options{
+		Writer:    &buf,
+		Formatter: &TextFormatter{MessageField: "msg"},
+		Level:     LevelInfo,
+		Recorder:  recorder,
+	})
+
+	log.With(Lazy("diff", func() interface{} {
+		calls++
+		return "+1 -1"
+	})).Debug("recorded")
+	if calls != 0 {
+		t.Errorf("\nWant: %d\nGot: %d", 0, calls)
+	}
+
+	log.Error("failed")
+	want := "msg=recorded diff=\"+1 -1\"\nmsg=failed\n"
+	if buf.String() != want || calls != 1 {
+		t.Errorf("\nWant: %s (1 call)\nGot: %s (%d calls)", want, buf.String(), calls)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: func() string { return "text" }, want: "text"},
+		{value: func() interface{} { return 42 }, want: "42"},
+		{value: LogValuerFunc(func() interface{} { return LogValuerFunc(func() interface{} { return "nested" }) }), want: "nested"},
+		{value: LogValuerFunc(func() interface{} { panic("boom") }), want: "!PANIC: boom"},
+		{value: func() string { var m map[string]int; m["x"] = 1; return "" }, want: "!PANIC: assignment to entry in nil map"},
+		{value: "plain", want: "plain"},
+	}
+
+	for _, tt := range tests {
+		fields := []Field{F("value", tt.value)}
+		resolveFields(fields)
+		if got := string(appendValue(nil, fields[0].Value)); got != tt.want {
+			t.Errorf("\nWant: %s\nGot: %s", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxOnceKeys bounds the number of keys remembered by Once. The oldest key is forgotten when it is exceeded.
+const maxOnceKeys = 1024
+
+// limiters holds the state of the rate limited call sites and keys of a logger and the loggers derived from it.
+type limiters struct {
+	m    sync.Map // map[limitKey]limiter
+	mu   sync.Mutex
+	once []limitKey // Once keys in the order they were added
+}
+
+// get returns the limiter stored for the key, creating it when not yet available.
+func (r *limiters) get(key limitKey, create func() limiter) limiter {
+	if lim, ok := r.m.Load(key); ok {
+		return lim.(limiter)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if lim, ok := r.m.Load(key); ok {
+		return lim.(limiter)
+	}
+	lim := create()
+	r.m.Store(key, lim)
+	if key.pc == 0 {
+		// keys of Once are given at runtime, call sites are bounded by the program
+		r.once = append(r.once, key)
+		if len(r.once) > maxOnceKeys {
+			r.m.Delete(r.once[0])
+			r.once = r.once[1:]
+		}
+	}
+	return lim
+}
+
+// limitKey identifies the state of a rate limited logger.
+type limitKey struct {
+	key      string
+	pc       uintptr
+	interval int64
+}
+
+// limiter decides whether a call through a rate limited logger is written.
+type limiter interface {
+	// allow reports whether the call is written and how many calls were skipped since the previous written call.
+	allow(now int64) (bool, uint64)
+}
+
+// onceLimiter writes only the first call.
+type onceLimiter struct {
+	done atomic.Bool
+}
+
+func (o *onceLimiter) allow(int64) (bool, uint64) {
+	return o.done.CompareAndSwap(false, true), 0
+}
+
+// everyNLimiter writes the first call and every Nth call thereafter.
+type everyNLimiter struct {
+	n     uint64
+	count atomic.Uint64
+}
+
+func (e *everyNLimiter) allow(int64) (bool, uint64) {
+	c := e.count.Add(1)
+	if c == 1 {
+		return true, 0
+	}
+	if (c-1)%e.n == 0 {
+		return true, e.n - 1
+	}
+	return false, 0
+}
+
+// everyLimiter writes at most one call per interval.
+type everyLimiter struct {
+	interval int64
+	next     atomic.Int64
+	skipped  atomic.Uint64
+}
+
+func (e *everyLimiter) allow(now int64) (bool, uint64) {
+	next := e.next.Load()
+	if now >= next && e.next.CompareAndSwap(next, now+e.interval) {
+		return true, e.skipped.Swap(0)
+	}
+	e.skipped.Add(1)
+	return false, 0
+}
+
+// Once returns a logger that only writes the first call for the given key, which is useful for deprecation notices.
+// The key is shared by the logger and the loggers derived from it. Only the most recent keys are remembered.
+func (l *Logger) Once(key string) *Logger {
+	return l.limited(limitKey{key: key}, func() limiter {
+		return &onceLimiter{}
+	})
+}
+
+// EveryN returns a logger that writes the first call and every nth call thereafter from the same call site.
+// Written lines carry a skipped field with the number of calls skipped in between.
+func (l *Logger) EveryN(n int) *Logger {
+	if n <= 1 {
+		return l
+	}
+	return l.limited(limitKey{pc: caller(), interval: int64(n)}, func() limiter {
+		return &everyNLimiter{n: uint64(n)}
+	})
+}
+
+// Every returns a logger that writes at most one call per interval from the same call site.
+// Written lines carry a skipped field with the number of calls skipped in between.
+func (l *Logger) Every(interval time.Duration) *Logger {
+	if interval <= 0 {
+		return l
+	}
+	return l.limited(limitKey{pc: caller(), interval: int64(interval)}, func() limiter {
+		return &everyLimiter{interval: int64(interval)}
+	})
+}
+
+// limited clones the logger with the limiter stored for the key, creating it when not yet available.
+// A logger that was not created by New or NewWithOptions has no limiters and is returned as is.
+func (l *Logger) limited(key limitKey, create func() limiter) *Logger {
+	if l.limiters == nil {
+		return l
+	}
+	clone := l.clone()
+	clone.limit = l.limiters.get(key, create)
+	return clone
+}
+
+// caller returns the program counter of the function calling the rate limited helper.
+func caller() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	return pcs[0]
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerOnce(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf)
+
+	for i := 0; i < 3; i++ {
+		log.Once("TestLoggerOnce").Warning("deprecated")
+	}
+
+	lines := strings.Count(buf.String(), "\n")
+	if lines != 1 {
+		t.Errorf("expected 1 line but got %d", lines)
+	}
+}
+
+func TestLoggerEveryN(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf)
+
+	for i := 0; i < 7; i++ {
+		log.EveryN(3).Info("tick")
+	}
+
+	// calls 1, 4 and 7 are written
+	got := buf.String()
+	if lines := strings.Count(got, "\n"); lines != 3 {
+		t.Errorf("expected 3 lines but got %d", lines)
+	}
+	if strings.Count(got, "skipped=2") != 2 {
+		t.Errorf("expected skipped calls to be reported, got: %s", got)
+	}
+}
+
+func TestLoggerEvery(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf)
+
+	for i := 0; i < 5; i++ {
+		log.Every(time.Hour).Error("retry failed")
+	}
+
+	lines := strings.Count(buf.String(), "\n")
+	if lines != 1 {
+		t.Errorf("expected 1 line but got %d", lines)
+	}
+}
+
+func TestLoggerOnce_perLogger(t *testing.T) {
+	var buf bytes.Buffer
+	first, second := New(&buf), New(&buf)
+
+	first.Once("TestLoggerOnce_perLogger").Warning("deprecated")
+	first.With(F("clone", true)).Once("TestLoggerOnce_perLogger").Warning("deprecated")
+	second.Once("TestLoggerOnce_perLogger").Warning("deprecated")
+
+	lines := strings.Count(buf.String(), "\n")
+	if lines != 2 {
+		t.Errorf("expected 2 lines but got %d", lines)
+	}
+}
+
+func TestLoggerOnce_zeroValue(t *testing.T) {
+	l := &Logger{}
+	if l.Once("TestLoggerOnce_zeroValue") != l || l.EveryN(10) != l || l.Every(time.Second) != l {
+		t.Errorf("expected the zero value logger to be returned unchanged")
+	}
+}
+
+func TestLimiters_boundedOnceKeys(t *testing.T) {
+	r := &limiters{}
+	create := func() limiter { return &onceLimiter{} }
+	first := r.get(limitKey{key: "0"}, create)
+	for i := 1; i <= maxOnceKeys; i++ {
+		r.get(limitKey{key: strconv.Itoa(i)}, create)
+	}
+
+	if len(r.once) != maxOnceKeys {
+		t.Errorf("expected %d keys but got %d", maxOnceKeys, len(r.once))
+	}
+	if r.get(limitKey{key: "0"}, create) == first {
+		t.Errorf("expected the oldest key to be forgotten")
+	}
+}
+
+func TestEveryLimiter(t *testing.T) {
+	lim := &everyLimiter{interval: int64(time.Second)}
+
+	if ok, _ := lim.allow(0); !ok {
+		t.Errorf("expected first call to be allowed")
+	}
+	for i := 0; i < 4; i++ {
+		if ok, _ := lim.allow(int64(time.Millisecond)); ok {
+			t.Errorf("expected call within interval to be skipped")
+		}
+	}
+	if ok, skipped := lim.allow(int64(time.Second)); !ok || skipped != 4 {
+		t.Errorf("expected call after interval to be allowed with 4 skipped, got %t and %d", ok, skipped)
+	}
+}
@@ -22,12 +22,19 @@ func SetDefaultLogger(l *logger.Logger) {
 
 // WithName clones the default logger but changes the name of the logger.
 func WithName(name string) *logger.Logger {
-	return log.WithName(name)
+	clone := log.WithName(name)
+	clone.Pos = 2 // the clone is no longer called through this package
+	return clone
+}
+
+// Flush writes any events that are held by the default logger.
+func Flush() {
+	log.Flush()
 }
 
 // Panic is just like Fatal except that it is followed by a call to panic.
-func Panic(message string) {
-	log.Panic(message)
+func Panic(message string, args ...interface{}) {
+	log.Panic(message, args...)
 }
 
 // Panicf is just like Fatalf except that it is followed by a call to panic.
@@ -36,8 +43,8 @@ func Panicf(format string, a ...interface{}) {
 }
 
 // Fatal logs a message at a Fatal Level.
-func Fatal(message string) {
-	log.Fatal(message)
+func Fatal(message string, args ...interface{}) {
+	log.Fatal(message, args...)
 }
 
 // Fatalf logs a message at Fatal level.
@@ -46,8 +53,8 @@ func Fatalf(format string, a ...interface{}) {
 }
 
 // Error logs a message at Error level.
-func Error(message string) {
-	log.Error(message)
+func Error(message string, args ...interface{}) {
+	log.Error(message, args...)
 }
 
 // Errorf logs a message at Error level.
@@ -56,8 +63,8 @@ func Errorf(format string, a ...interface{}) {
 }
 
 // Warning logs a message at Warning level
-func Warning(message string) {
-	log.Warning(message)
+func Warning(message string, args ...interface{}) {
+	log.Warning(message, args...)
 }
 
 // Warningf logs a message at Warning level.
@@ -66,8 +73,8 @@ func Warningf(format string, a ...interface{}) {
 }
 
 // Info logs a message at Info level.
-func Info(message string) {
-	log.Info(message)
+func Info(message string, args ...interface{}) {
+	log.Info(message, args...)
 }
 
 // Infof logs a message at Info level.
@@ -76,8 +83,8 @@ func Infof(format string, a ...interface{}) {
 }
 
 // Debug logs a message at Debug level.
-func Debug(message string) {
-	log.Debug(message)
+func Debug(message string, args ...interface{}) {
+	log.Debug(message, args...)
 }
 
 // Debugf logs a message at Debug level.
@@ -86,8 +93,8 @@ func Debugf(format string, a ...interface{}) {
 }
 
 // Trace logs a message at Debug level.
-func Trace(message string) {
-	log.Trace(message)
+func Trace(message string, args ...interface{}) {
+	log.Trace(message, args...)
 }
 
 // Tracef logs a message at Debug level.
@@ -2,6 +2,7 @@ package logger
 
 // Import packages
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -89,8 +90,22 @@ type Logger struct {
 	name      string
 	level     Level
 	w         io.Writer
+	fields    []Field
+	sampler   Sampler
+	limit     limiter
+	limiters  *limiters
+	collapse  *collapser
+	budget    *Budget
+	recorder  *Recorder
+	redactor  *Redactor
+	buffer    *Buffer
+	clock     Clock
+	ctx       context.Context
 	Pos       int
 
+	// fields stripped before formatting by a deterministic logger
+	processFields []string
+
 	// only used for testing ...
 	ignoreExit bool
 }
@@ -101,6 +116,33 @@ type Options struct {
 	Formatter Formatter
 	Level     Level
 	Writer    io.Writer
+
+	// Sampler optionally drops similar events before they are formatted to tame log floods.
+	Sampler Sampler
+
+	// CollapseRepeats enables collapsing identical consecutive events into a single "last message repeated N times"
+	// line. Repeats are held until a different event is logged, the duration elapses or the logger is flushed.
+	CollapseRepeats time.Duration
+
+	// Budget optionally limits the volume of formatted lines, shedding lower levels first.
+	Budget *Budget
+
+	// Recorder optionally keeps the most recent events at every level in memory, to dump them when an error occurs.
+	Recorder *Recorder
+
+	// Redactor optionally masks sensitive values such as IBANs, card numbers and tokens before events are formatted.
+	Redactor *Redactor
+
+	// Clock provides the time of events, it defaults to the SystemClock.
+	Clock Clock
+
+	// Deterministic makes the output reproducible for golden tests. Events get the DeterministicTime unless a Clock
+	// is given and the ProcessFields are stripped before formatting.
+	Deterministic bool
+
+	// ProcessFields are the keys of the fields a deterministic logger strips, since their values differ per run.
+	// Defaults to the trace_id, span_id, trace_flags, request_id, duration and pid fields, an empty list strips none.
+	ProcessFields []string
 }
 
 // New returns a new logger instance. It will create a logger with optimistic defaults for ease of use.
@@ -132,15 +174,51 @@ func NewWithOptions(opts Options) *Logger {
 		opts.Formatter = defaultFormatter
 	}
 
+	if opts.Clock == nil {
+		if opts.Deterministic {
+			opts.Clock = NewFixedClock(DeterministicTime)
+		} else {
+			opts.Clock = SystemClock
+		}
+	}
+
+	var processFields []string
+	if opts.Deterministic {
+		processFields = opts.ProcessFields
+		if processFields == nil {
+			processFields = []string{TraceIDField, SpanIDField, TraceFlagsField, "request_id", "duration", "pid"}
+		}
+	}
+
+	var collapse *collapser
+	if opts.CollapseRepeats > 0 {
+		collapse = newCollapser(opts.CollapseRepeats, opts.Clock)
+	}
+
 	return &Logger{
 		w:         opts.Writer,
 		formatter: opts.Formatter,
 		name:      opts.Name,
 		level:     opts.Level,
+		sampler:   opts.Sampler,
+		limiters:  &limiters{},
+		collapse:  collapse,
+		budget:    opts.Budget,
+		recorder:  opts.Recorder,
+		redactor:  opts.Redactor,
+		clock:     opts.Clock,
 		Pos:       2,
+
+		processFields: processFields,
 	}
 }
 
+// Clock returns the clock that provides the time of the events of the logger. Packages that measure durations
+// for a logger should use it, so a custom clock controls them as well.
+func (l *Logger) Clock() Clock {
+	return l.clock
+}
+
 // SetLogLevel will assign a new log level to the logger instance.
 func (l *Logger) SetLogLevel(lvl Level) {
 	l.level = lvl
@@ -165,64 +243,220 @@ func (l *Logger) should(lvl Level) bool {
 	}
 }
 
+// Enabled returns true when events at the given level are written by the logger.
+func (l *Logger) Enabled(lvl Level) bool {
+	return l.should(lvl)
+}
+
 // log is the function available to user to log message, lvl specifies the severity of the message
 // whilst message contains the actual information.
-func (l *Logger) log(lvl Level, message string) {
+func (l *Logger) log(lvl Level, message string, args []interface{}) {
 	enabled := l.should(lvl)
-	if !enabled {
+	if !enabled && l.recorder == nil {
 		return // skip log line
 	}
 
 	// create new event
+	e := l.event(lvl, message)
+	e.args = args
+	if enabled {
+		// resolve lazy values once, so every stage and a recorder dump see the same values
+		resolveEvent(e)
+	}
+
+	if l.recorder != nil {
+		l.recorder.record(l, e)
+		if !enabled {
+			putEvent(e)
+			return // only recorded
+		}
+	}
+
+	if l.limit != nil {
+		allowed, skipped := l.limit.allow(e.Time.UnixNano())
+		if !allowed {
+			putEvent(e)
+			return // skipped by rate limited helper
+		}
+		if skipped > 0 {
+			e.Fields = append(e.Fields, F("skipped", skipped))
+		}
+	}
+
+	if l.sampler != nil {
+		keep := l.sampler.Sample(e)
+		l.reportSuppressed(e.Time)
+		if !keep {
+			putEvent(e)
+			return // dropped by sampler
+		}
+	}
+
+	if l.buffer != nil {
+		l.buffer.add(e)
+	} else if l.collapse != nil {
+		l.collapse.write(l, e)
+	} else {
+		l.write(e)
+	}
+
+	// put event back in event pool
+	putEvent(e)
+}
+
+// event retrieves a new event from the pool populated with the logger information.
+func (l *Logger) event(lvl Level, message string) *Event {
 	e := getEvent()
-	e.Time = time.Now()
+	e.Time = l.clock.Now()
 	e.Module = l.name
 	e.Level = lvl
 	e.Message = message
+	e.Fields = append(e.Fields, l.fields...)
+
+	if l.ctx != nil {
+		// enrich event with the span carried by the context
+		if sc, ok := SpanFromContext(l.ctx); ok {
+			e.Fields = append(e.Fields, F(TraceIDField, sc.TraceID), F(SpanIDField, sc.SpanID), F(TraceFlagsField, sc.Flags))
+		}
+	}
 
 	if pf, ok := l.formatter.(*PrettyFormatter); ok && pf.AppendSource {
 		// append caller information for pretty formatter
-		_, filename, line, _ := runtime.Caller(l.Pos)
+		_, filename, line, _ := runtime.Caller(l.Pos + 1)
 		e.Filename = path.Base(filename)
 		e.Line = line
 	}
+	return e
+}
+
+// prepare resolves, strips and redacts the fields of the event before it is formatted.
+func (l *Logger) prepare(e *Event) {
+	resolveEvent(e) // resolves recorded events that were not enabled
+	if len(l.processFields) > 0 {
+		e.Fields = stripFields(e.Fields, l.processFields)
+	}
+	if l.redactor != nil {
+		l.redactor.redact(e)
+	}
+}
+
+// write formats the event using the logger formatter and writes the result to the writer.
+func (l *Logger) write(e *Event) {
+	l.prepare(e)
 
 	// format using logger formatter -> this will update internal buffer of event
 	l.formatter.Format(e)
-	if _, err := l.w.Write(e.buf); err != nil {
+
+	if l.budget != nil {
+		l.reportShed(e.Time)
+		if !l.budget.allow(e.Level, len(e.buf), e.Time) {
+			return // shed by budget
+		}
+	}
+
+	l.output(e.buf)
+	if e.recorder != nil {
+		e.recorder.written(e)
+	}
+}
+
+// output writes the formatted line to the writer.
+func (l *Logger) output(p []byte) {
+	if _, err := l.w.Write(p); err != nil {
 		fmt.Fprintf(os.Stderr, "logger: could not write event: %v\n", err)
 	}
+}
 
-	// put event back in event pool
-	putEvent(e)
+// reportShed writes a warning for every level the budget shed lines for, bypassing the budget itself.
+func (l *Logger) reportShed(now time.Time) {
+	for _, s := range l.budget.shed(now) {
+		e := l.event(LevelWarning, fmt.Sprintf("shed %d %s events over budget", s.events, s.level))
+		e.Time = now
+		e.Fields = append(e.Fields, F("shed_level", s.level.String()), F("shed_events", s.events), F("shed_bytes", s.bytes))
+		l.formatter.Format(e)
+		l.output(e.buf)
+		putEvent(e)
+	}
+}
+
+// reportSuppressed writes a summary event for every key the sampler suppressed events for.
+func (l *Logger) reportSuppressed(now time.Time) {
+	for _, s := range l.sampler.Suppressed(now) {
+		e := l.event(s.Level, fmt.Sprintf("suppressed %d similar events", s.Count))
+		e.Time = now
+		if s.Key != "" {
+			e.Fields = append(e.Fields, F("sample_key", s.Key))
+		}
+		e.Fields = append(e.Fields, F("suppressed", s.Count))
+		l.write(e)
+		putEvent(e)
+	}
+}
+
+// Flush writes any events that are held by the logger. It should be called before the application exits.
+func (l *Logger) Flush() {
+	if l.collapse != nil {
+		l.collapse.flush()
+	}
+}
+
+// clone returns a shallow copy of the logger instance.
+func (l *Logger) clone() *Logger {
+	clone := *l
+	return &clone
 }
 
 // WithName clones the logger instance and changes the name of the logger.
 func (l *Logger) WithName(name string) *Logger {
-	clone := &Logger{
-		formatter: l.formatter,
-		name:      name,
-		w:         l.w,
-		level:     l.level,
-	}
+	clone := l.clone()
+	clone.name = name
 	return clone
 }
 
+// WithContext clones the logger instance and binds it to the context. Events logged by the clone are enriched
+// with the trace_id, span_id and trace_flags fields when the context carries a span, see ContextWithSpan.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	clone := l.clone()
+	clone.ctx = ctx
+	return clone
+}
+
+// With clones the logger instance and attaches the fields to every event logged by the clone.
+func (l *Logger) With(fields ...Field) *Logger {
+	clone := l.clone()
+	clone.fields = append(l.fields[:len(l.fields):len(l.fields)], fields...)
+	return clone
+}
+
+// Log logs a message at the given level. Unlike Fatal and Panic it never exits or panics,
+// which makes it suitable for levels that are determined at runtime.
+//
+// When arguments are given, the message is a template of which the {Name} holes are replaced by the arguments
+// in order. The template is kept on the event and every argument is captured as a field named after its hole.
+// The same applies to Panic, Fatal, Error, Warning, Info, Debug and Trace.
+func (l *Logger) Log(lvl Level, message string, args ...interface{}) {
+	l.log(lvl, message, args)
+}
+
 // Panic is just like Fatal except that it is followed by a call to panic.
-func (l *Logger) Panic(message string) {
-	l.log(LevelFatal, message)
+func (l *Logger) Panic(message string, args ...interface{}) {
+	l.log(LevelFatal, message, args)
+	if len(args) > 0 {
+		message = renderTemplate(message, args)
+	}
 	panic(message)
 }
 
 // Panicf is just like Fatalf except that it is followed by a call to panic.
 func (l *Logger) Panicf(format string, a ...interface{}) {
-	l.log(LevelFatal, fmt.Sprintf(format, a...))
+	l.log(LevelFatal, fmt.Sprintf(format, a...), nil)
 	panic(fmt.Sprintf(format, a...))
 }
 
 // Fatal logs a message at a Fatal Level that is followed by an OS exit code.
-func (l *Logger) Fatal(message string) {
-	l.log(LevelFatal, message)
+func (l *Logger) Fatal(message string, args ...interface{}) {
+	l.log(LevelFatal, message, args)
+	l.Flush()
 	if !l.ignoreExit {
 		os.Exit(1)
 	}
@@ -230,58 +464,59 @@ func (l *Logger) Fatal(message string) {
 
 // Fatalf logs a message at Fatal level that is followed by an OS exit code.
 func (l *Logger) Fatalf(format string, a ...interface{}) {
-	l.log(LevelFatal, fmt.Sprintf(format, a...))
+	l.log(LevelFatal, fmt.Sprintf(format, a...), nil)
+	l.Flush()
 	if !l.ignoreExit {
 		os.Exit(1)
 	}
 }
 
 // Error logs a message at Error level.
-func (l *Logger) Error(message string) {
-	l.log(LevelError, message)
+func (l *Logger) Error(message string, args ...interface{}) {
+	l.log(LevelError, message, args)
 }
 
 // Errorf logs a message at Error level.
 func (l *Logger) Errorf(format string, a ...interface{}) {
-	l.log(LevelError, fmt.Sprintf(format, a...))
+	l.log(LevelError, fmt.Sprintf(format, a...), nil)
 }
 
 // Warning logs a message at Warning level
-func (l *Logger) Warning(message string) {
-	l.log(LevelWarning, message)
+func (l *Logger) Warning(message string, args ...interface{}) {
+	l.log(LevelWarning, message, args)
 }
 
 // Warningf logs a message at Warning level.
 func (l *Logger) Warningf(format string, a ...interface{}) {
-	l.log(LevelWarning, fmt.Sprintf(format, a...))
+	l.log(LevelWarning, fmt.Sprintf(format, a...), nil)
 }
 
 // Info logs a message at Info level.
-func (l *Logger) Info(message string) {
-	l.log(LevelInfo, message)
+func (l *Logger) Info(message string, args ...interface{}) {
+	l.log(LevelInfo, message, args)
 }
 
 // Infof logs a message at Info level.
 func (l *Logger) Infof(format string, a ...interface{}) {
-	l.log(LevelInfo, fmt.Sprintf(format, a...))
+	l.log(LevelInfo, fmt.Sprintf(format, a...), nil)
 }
 
 // Debug logs a message at Debug level.
-func (l *Logger) Debug(message string) {
-	l.log(LevelDebug, message)
+func (l *Logger) Debug(message string, args ...interface{}) {
+	l.log(LevelDebug, message, args)
 }
 
 // Debugf logs a message at Debug level.
 func (l *Logger) Debugf(format string, a ...interface{}) {
-	l.log(LevelDebug, fmt.Sprintf(format, a...))
+	l.log(LevelDebug, fmt.Sprintf(format, a...), nil)
 }
 
 // Trace logs a message at Debug level.
-func (l *Logger) Trace(message string) {
-	l.log(LevelTrace, message)
+func (l *Logger) Trace(message string, args ...interface{}) {
+	l.log(LevelTrace, message, args)
 }
 
 // Tracef logs a message at Debug level.
 func (l *Logger) Tracef(format string, a ...interface{}) {
-	l.log(LevelTrace, fmt.Sprintf(format, a...))
+	l.log(LevelTrace, fmt.Sprintf(format, a...), nil)
 }
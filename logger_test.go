@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -172,3 +173,33 @@ func TestLogLevelGlobal(t *testing.T) {
 		buf.Reset()
 	}
 }
+
+func TestLoggerWith(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Name: "testing", Writer: &buf}).With(F("mandate", 42))
+
+	log.With(F("customer", "john doe")).Info("signed")
+
+	got := buf.String()
+	if !strings.HasSuffix(got, "msg=signed mandate=42 customer=\"john doe\"\n") {
+		t.Errorf("expected log message to contain fields, got: %s", got)
+	}
+}
+
+// syncBuffer is a bytes.Buffer that can be used by concurrent writers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
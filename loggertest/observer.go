@@ -0,0 +1,240 @@
+// Package loggertest provides helpers to assert on the events logged by code under test.
+package loggertest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+// update rewrites golden files instead of comparing them, run the tests with -loggertest.update.
+var update = flag.Bool("loggertest.update", false, "update the golden files of loggertest")
+
+// Epoch is the time of the first event logged by an observer without a Clock, every next event is one millisecond later.
+var Epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Observed records copies of the events logged through an observer logger. It is safe for concurrent use.
+type Observed struct {
+	mu      sync.Mutex
+	entries Entries
+}
+
+// NewObserver returns a logger that records every event instead of writing it, and the recorded events.
+// The formatter and writer of the options are ignored and the level defaults to LevelTrace. Unless a Clock is given
+// the events get a deterministic time starting at Epoch to allow snapshot comparisons.
+func NewObserver(opts logger.Options) (*logger.Logger, *Observed) {
+	o := &Observed{}
+	if opts.Level <= 0 {
+		opts.Level = logger.LevelTrace
+	}
+	if opts.Clock == nil {
+		opts.Clock = logger.NewSteppingClock(Epoch, time.Millisecond)
+	}
+	opts.Formatter = observer{o}
+	opts.Writer = nil
+	return logger.NewWithOptions(opts), o
+}
+
+// observer is the formatter that records the events.
+type observer struct {
+	o *Observed
+}
+
+func (f observer) Format(e *logger.Event) {
+	f.o.mu.Lock()
+	defer f.o.mu.Unlock()
+	f.o.entries = append(f.o.entries, e.Clone())
+}
+
+// All returns all recorded events.
+func (o *Observed) All() Entries {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append(Entries(nil), o.entries...)
+}
+
+// Len returns the number of recorded events.
+func (o *Observed) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Reset removes all recorded events.
+func (o *Observed) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = nil
+}
+
+// FilterLevel returns the recorded events at the given level.
+func (o *Observed) FilterLevel(lvl logger.Level) Entries {
+	return o.All().FilterLevel(lvl)
+}
+
+// FilterModule returns the recorded events of the named logger.
+func (o *Observed) FilterModule(name string) Entries {
+	return o.All().FilterModule(name)
+}
+
+// FilterMessage returns the recorded events of which the message matches the regular expression.
+func (o *Observed) FilterMessage(pattern string) Entries {
+	return o.All().FilterMessage(pattern)
+}
+
+// FilterField returns the recorded events with a field of the given key and value.
+func (o *Observed) FilterField(key string, value interface{}) Entries {
+	return o.All().FilterField(key, value)
+}
+
+// Snapshot returns the deterministic text representation of the recorded events, see Entries.Snapshot.
+func (o *Observed) Snapshot() string {
+	return o.All().Snapshot()
+}
+
+// Entries are recorded event copies which can be filtered further.
+type Entries []*logger.Event
+
+// Len returns the number of events.
+func (e Entries) Len() int {
+	return len(e)
+}
+
+// Messages returns the messages of the events.
+func (e Entries) Messages() []string {
+	messages := make([]string, len(e))
+	for i, entry := range e {
+		messages[i] = entry.Message
+	}
+	return messages
+}
+
+// Filter returns the events for which the function returns true.
+func (e Entries) Filter(fn func(e *logger.Event) bool) Entries {
+	var filtered Entries
+	for _, entry := range e {
+		if fn(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// FilterLevel returns the events at the given level.
+func (e Entries) FilterLevel(lvl logger.Level) Entries {
+	return e.Filter(func(e *logger.Event) bool {
+		return e.Level == lvl
+	})
+}
+
+// FilterModule returns the events of the named logger.
+func (e Entries) FilterModule(name string) Entries {
+	return e.Filter(func(e *logger.Event) bool {
+		return e.Module == name
+	})
+}
+
+// FilterMessage returns the events of which the message matches the regular expression.
+// It panics when the pattern is not a valid regular expression.
+func (e Entries) FilterMessage(pattern string) Entries {
+	re := regexp.MustCompile(pattern)
+	return e.Filter(func(e *logger.Event) bool {
+		return re.MatchString(e.Message)
+	})
+}
+
+// FilterField returns the events with a field of the given key and value. Values are compared by their
+// textual representation, so F("id", 42) matches a filter on the value "42".
+func (e Entries) FilterField(key string, value interface{}) Entries {
+	want := fmt.Sprint(value)
+	return e.Filter(func(e *logger.Event) bool {
+		for _, f := range e.Fields {
+			if f.Key == key && fmt.Sprint(f.Value) == want {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Snapshot returns a deterministic text representation of the events, one line per event:
+//
+// time level [module] message key=value
+func (e Entries) Snapshot() string {
+	var b strings.Builder
+	for _, entry := range e {
+		b.WriteString(entry.Time.UTC().Format(time.RFC3339Nano))
+		b.WriteByte(' ')
+		b.WriteString(entry.Level.String())
+		if entry.Module != "" {
+			b.WriteString(" [")
+			b.WriteString(entry.Module)
+			b.WriteByte(']')
+		}
+		b.WriteByte(' ')
+		b.WriteString(entry.Message)
+		for _, f := range entry.Fields {
+			fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// RequireLogged fails the test immediately when none of the recorded events is at the given level with a message
+// matching the regular expression and all given fields. It returns the first matching event.
+func RequireLogged(t testing.TB, o *Observed, lvl logger.Level, pattern string, fields ...logger.Field) *logger.Event {
+	t.Helper()
+
+	matches := o.FilterLevel(lvl).FilterMessage(pattern)
+	for _, f := range fields {
+		matches = matches.FilterField(f.Key, f.Value)
+	}
+	if len(matches) == 0 {
+		t.Fatalf("loggertest: no %s event matching %q with fields %v, recorded events:\n%s", lvl, pattern, fields, o.Snapshot())
+	}
+	return matches[0]
+}
+
+// RequireNotLogged fails the test immediately when a recorded event is at the given level with a message
+// matching the regular expression.
+func RequireNotLogged(t testing.TB, o *Observed, lvl logger.Level, pattern string) {
+	t.Helper()
+
+	if matches := o.FilterLevel(lvl).FilterMessage(pattern); len(matches) > 0 {
+		t.Fatalf("loggertest: unexpected %s event matching %q, recorded events:\n%s", lvl, pattern, matches.Snapshot())
+	}
+}
+
+// AssertGolden compares the snapshot of the recorded events with the golden file and reports an error when they
+// differ. Run the tests with -loggertest.update to create or update the golden file.
+func AssertGolden(t testing.TB, o *Observed, path string) {
+	t.Helper()
+
+	got := o.Snapshot()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("loggertest: unable to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("loggertest: unable to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("loggertest: unable to read golden file, run with -loggertest.update to create it: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("loggertest: snapshot does not match golden file %s\nWant:\n%s\nGot:\n%s", path, want, got)
+	}
+}
@@ -0,0 +1,59 @@
+package loggertest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+func TestObserver(t *testing.T) {
+	l, observed := NewObserver(logger.Options{Name: "orders"})
+
+	l.With(logger.F("order", 42)).Info("order created")
+	l.WithName("payments").Warning("payment retried")
+	l.Debugf("%d items", 3)
+
+	if observed.Len() != 3 {
+		t.Fatalf("\nWant: %d\nGot: %d", 3, observed.Len())
+	}
+	if n := observed.FilterLevel(logger.LevelWarning).Len(); n != 1 {
+		t.Errorf("\nWant: %d\nGot: %d", 1, n)
+	}
+	if n := observed.FilterModule("orders").Len(); n != 2 {
+		t.Errorf("\nWant: %d\nGot: %d", 2, n)
+	}
+	if messages := observed.FilterMessage(`^\d+ items$`).Messages(); len(messages) != 1 || messages[0] != "3 items" {
+		t.Errorf("\nWant: %v\nGot: %v", []string{"3 items"}, messages)
+	}
+	if n := observed.FilterField("order", "42").Len(); n != 1 {
+		t.Errorf("\nWant: %d\nGot: %d", 1, n)
+	}
+
+	e := RequireLogged(t, observed, logger.LevelInfo, "created", logger.F("order", 42))
+	if e.Time != Epoch {
+		t.Errorf("\nWant: %s\nGot: %s", Epoch, e.Time)
+	}
+	RequireNotLogged(t, observed, logger.LevelError, ".")
+
+	observed.Reset()
+	if observed.Len() != 0 {
+		t.Errorf("\nWant: %d\nGot: %d", 0, observed.Len())
+	}
+}
+
+func TestObserver_Snapshot(t *testing.T) {
+	l, observed := NewObserver(logger.Options{Name: "orders"})
+
+	l.With(logger.F("order", 42), logger.F("took", 15*time.Millisecond)).Info("order created")
+	l.Error("payment failed")
+
+	want := "2000-01-01T00:00:00Z info [orders] order created order=42 took=15ms\n" +
+		"2000-01-01T00:00:00.001Z error [orders] payment failed\n"
+	if got := observed.Snapshot(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+
+	AssertGolden(t, observed, filepath.Join("testdata", "snapshot.golden"))
+}
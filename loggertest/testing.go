@@ -0,0 +1,139 @@
+package loggertest
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/twikey/go-logger"
+)
+
+// module is the import path of the logger package, its frames are never reported as source.
+var module = reflect.TypeOf(logger.Logger{}).PkgPath()
+
+// helpers contains the functions marked by Helper.
+var helpers sync.Map
+
+// Helper marks the calling function as a logging helper, like testing.T.Helper does for test helpers.
+// The source of events logged through a loggertest.New logger then points at the caller of the helper.
+func Helper() {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		helpers.Store(fn.Name(), struct{}{})
+	}
+}
+
+// New returns a logger that writes its events through t.Log. Events are only shown when the test fails,
+// unless the tests run with -v in which case they are shown immediately. Every event gets a source field
+// pointing at the code that logged it. Logging after the test completed panics, since it indicates a
+// goroutine that outlives its test.
+//
+// The testing package does not expose the functions marked by t.Helper, so those are still reported as the
+// source, mark logging helpers with Helper instead. The file and line prefixed by t.Log point at this package.
+func New(t testing.TB) *logger.Logger {
+	return newLogger(t, testing.Verbose())
+}
+
+func newLogger(t testing.TB, verbose bool) *logger.Logger {
+	w := &testWriter{t: t, verbose: verbose}
+	t.Cleanup(w.done)
+
+	return logger.NewWithOptions(logger.Options{
+		Level: logger.LevelTrace,
+		Formatter: &sourceFormatter{
+			Formatter: &logger.TextFormatter{
+				LevelField:   "lvl",
+				MessageField: "msg",
+			},
+		},
+		Writer: w,
+	})
+}
+
+// sourceFormatter adds the location of the code that logged the event as a field.
+type sourceFormatter struct {
+	logger.Formatter
+}
+
+func (f *sourceFormatter) Format(e *logger.Event) {
+	if src := source(); src != "" {
+		e.Fields = append(e.Fields, logger.F("source", src))
+	}
+	f.Formatter.Format(e)
+}
+
+// source returns the location of the first caller outside the logger packages and helper functions.
+func source() string {
+	var pcs [64]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !internal(frame) {
+			return path.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// internal reports whether the frame belongs to the logger packages or a marked helper.
+func internal(frame runtime.Frame) bool {
+	if _, ok := helpers.Load(frame.Function); ok {
+		return true
+	}
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+	return strings.HasPrefix(frame.Function, module+".") ||
+		strings.HasPrefix(frame.Function, module+"/log.") ||
+		strings.HasPrefix(frame.Function, module+"/loggertest.")
+}
+
+// testWriter buffers the formatted events until the test completes.
+type testWriter struct {
+	mu        sync.Mutex
+	t         testing.TB
+	verbose   bool
+	lines     []string
+	completed bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	line := strings.TrimSuffix(string(p), "\n")
+	if w.completed {
+		panic(fmt.Sprintf("loggertest: logged after %s has completed: %s", w.t.Name(), line))
+	}
+	if w.verbose {
+		w.t.Log(line)
+	} else {
+		w.lines = append(w.lines, line)
+	}
+	return len(p), nil
+}
+
+// done writes the buffered events when the test failed and rejects any later events.
+func (w *testWriter) done() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.completed = true
+	if w.t.Failed() {
+		for _, line := range w.lines {
+			w.t.Log(line)
+		}
+	}
+	w.lines = nil
+}
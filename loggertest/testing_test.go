@@ -0,0 +1,93 @@
+package loggertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/twikey/go-logger"
+)
+
+// fakeT records the calls the logger makes to the test.
+type fakeT struct {
+	testing.TB
+	failed   bool
+	logs     []string
+	cleanups []func()
+}
+
+func (t *fakeT) Name() string            { return "TestFake" }
+func (t *fakeT) Failed() bool            { return t.failed }
+func (t *fakeT) Cleanup(fn func())       { t.cleanups = append(t.cleanups, fn) }
+func (t *fakeT) Log(args ...interface{}) { t.logs = append(t.logs, fmt.Sprint(args...)) }
+
+func (t *fakeT) complete() {
+	for _, fn := range t.cleanups {
+		fn()
+	}
+}
+
+func TestNew(t *testing.T) {
+	ft := &fakeT{}
+	l := newLogger(ft, false)
+	l.Info("hidden")
+	ft.complete()
+
+	if len(ft.logs) != 0 {
+		t.Errorf("\nWant: %v\nGot: %v", nil, ft.logs)
+	}
+}
+
+func TestNew_failed(t *testing.T) {
+	ft := &fakeT{}
+	l := newLogger(ft, false)
+	l.With(logger.F("order", 42)).Info("order created")
+	ft.failed = true
+	ft.complete()
+
+	want := "lvl=info msg=\"order created\" order=42 source=testing_test.go:44"
+	if len(ft.logs) != 1 || ft.logs[0] != want {
+		t.Errorf("\nWant: %v\nGot: %v", []string{want}, ft.logs)
+	}
+}
+
+func TestNew_verbose(t *testing.T) {
+	ft := &fakeT{}
+	l := newLogger(ft, true)
+	l.Warning("shown")
+
+	want := "lvl=warn msg=shown source=testing_test.go:57"
+	if len(ft.logs) != 1 || ft.logs[0] != want {
+		t.Errorf("\nWant: %v\nGot: %v", []string{want}, ft.logs)
+	}
+}
+
+// logOrder is a helper of which the caller is reported as source.
+func logOrder(l *logger.Logger) {
+	Helper()
+	l.Info("order")
+}
+
+func TestNew_helper(t *testing.T) {
+	ft := &fakeT{}
+	l := newLogger(ft, true)
+	logOrder(l)
+
+	if len(ft.logs) != 1 || !strings.HasSuffix(ft.logs[0], "source=testing_test.go:74") {
+		t.Errorf("\nWant: %s\nGot: %v", "source=testing_test.go:74", ft.logs)
+	}
+}
+
+func TestNew_afterCompletion(t *testing.T) {
+	ft := &fakeT{}
+	l := newLogger(ft, false)
+	ft.complete()
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), "logged after TestFake has completed") {
+			t.Errorf("\nWant: %s\nGot: %v", "logged after TestFake has completed", r)
+		}
+	}()
+	l.Info("leaked")
+}
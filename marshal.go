@@ -0,0 +1,420 @@
+package logger
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+)
+
+// ObjectMarshaler is implemented by types that encode themselves as an object with fields, without reflection.
+// The TextFormatter flattens objects into dotted keys, the JSONFormatter nests them and the other formatters
+// write them as {key=value}.
+type ObjectMarshaler interface {
+	LogObject(enc ObjectEncoder)
+}
+
+// ArrayMarshaler is implemented by types that encode themselves as an array, without reflection.
+// The TextFormatter flattens arrays into keys with the index, the JSONFormatter nests them and the other formatters
+// write them as [value value].
+type ArrayMarshaler interface {
+	LogArray(enc ArrayEncoder)
+}
+
+// ObjectMarshalerFunc adapts a function to an ObjectMarshaler.
+type ObjectMarshalerFunc func(enc ObjectEncoder)
+
+// LogObject implements the ObjectMarshaler interface.
+func (f ObjectMarshalerFunc) LogObject(enc ObjectEncoder) {
+	f(enc)
+}
+
+// ArrayMarshalerFunc adapts a function to an ArrayMarshaler.
+type ArrayMarshalerFunc func(enc ArrayEncoder)
+
+// LogArray implements the ArrayMarshaler interface.
+func (f ArrayMarshalerFunc) LogArray(enc ArrayEncoder) {
+	f(enc)
+}
+
+// ObjectEncoder adds the fields of an object to the event.
+type ObjectEncoder interface {
+	AddString(key, value string)
+	AddInt(key string, value int64)
+	AddUint(key string, value uint64)
+	AddFloat(key string, value float64)
+	AddBool(key string, value bool)
+	AddDuration(key string, value time.Duration)
+	AddTime(key string, value time.Time)
+	AddObject(key string, value ObjectMarshaler)
+	AddArray(key string, value ArrayMarshaler)
+
+	// AddValue adds a value of any type like a Field value.
+	AddValue(key string, value interface{})
+}
+
+// ArrayEncoder adds the elements of an array to the event.
+type ArrayEncoder interface {
+	AppendString(value string)
+	AppendInt(value int64)
+	AppendUint(value uint64)
+	AppendFloat(value float64)
+	AppendBool(value bool)
+	AppendDuration(value time.Duration)
+	AppendTime(value time.Time)
+	AppendObject(value ObjectMarshaler)
+	AppendArray(value ArrayMarshaler)
+
+	// AppendValue appends a value of any type like a Field value.
+	AppendValue(value interface{})
+}
+
+// textEncoder encodes objects and arrays as logfmt fields with dotted keys, such as mandate.debtor.city=Ghent
+// and mandate.tags.0=recurring.
+type textEncoder struct {
+	t      *TextFormatter
+	e      *Event
+	prefix []byte // sanitized keys of the enclosing objects and arrays
+	index  int    // index of the next array element
+}
+
+// reset prepares the encoder of the event for a top level field.
+func (enc *textEncoder) reset(t *TextFormatter, e *Event) *textEncoder {
+	enc.t = t
+	enc.e = e
+	enc.prefix = enc.prefix[:0]
+	enc.index = 0
+	return enc
+}
+
+// key appends the prefixed key of an object field.
+func (enc *textEncoder) key(key string) {
+	enc.e.buf = append(enc.e.buf, enc.prefix...)
+	enc.t.key(enc.e, key)
+	enc.e.buf = append(enc.e.buf, equal)
+}
+
+// element appends the prefixed index of an array element.
+func (enc *textEncoder) element() {
+	enc.e.buf = append(enc.e.buf, enc.prefix...)
+	enc.e.buf = strconv.AppendInt(enc.e.buf, int64(enc.index), 10)
+	enc.e.buf = append(enc.e.buf, equal)
+	enc.index++
+}
+
+func (enc *textEncoder) end() {
+	enc.e.buf = append(enc.e.buf, space)
+}
+
+// nest encodes the object or array with the prefix extended from n by a dot.
+func (enc *textEncoder) nest(n int, object ObjectMarshaler, array ArrayMarshaler) {
+	enc.prefix = append(enc.prefix, '.')
+	index := enc.index
+	enc.index = 0
+	if object != nil {
+		object.LogObject(enc)
+	} else {
+		array.LogArray(enc)
+	}
+	enc.prefix, enc.index = enc.prefix[:n], index
+}
+
+func (enc *textEncoder) AddString(key, value string) {
+	enc.key(key)
+	enc.t.valueString(enc.e, value)
+	enc.end()
+}
+
+func (enc *textEncoder) AddInt(key string, value int64) {
+	enc.key(key)
+	enc.e.buf = strconv.AppendInt(enc.e.buf, value, 10)
+	enc.end()
+}
+
+func (enc *textEncoder) AddUint(key string, value uint64) {
+	enc.key(key)
+	enc.e.buf = strconv.AppendUint(enc.e.buf, value, 10)
+	enc.end()
+}
+
+func (enc *textEncoder) AddFloat(key string, value float64) {
+	enc.key(key)
+	enc.e.buf = strconv.AppendFloat(enc.e.buf, value, 'g', -1, 64)
+	enc.end()
+}
+
+func (enc *textEncoder) AddBool(key string, value bool) {
+	enc.key(key)
+	enc.e.buf = strconv.AppendBool(enc.e.buf, value)
+	enc.end()
+}
+
+func (enc *textEncoder) AddDuration(key string, value time.Duration) {
+	enc.key(key)
+	enc.e.buf = append(enc.e.buf, value.String()...)
+	enc.end()
+}
+
+func (enc *textEncoder) AddTime(key string, value time.Time) {
+	enc.key(key)
+	enc.e.buf = value.AppendFormat(enc.e.buf, time.RFC3339Nano)
+	enc.end()
+}
+
+func (enc *textEncoder) AddObject(key string, value ObjectMarshaler) {
+	n := len(enc.prefix)
+	enc.prefix = enc.t.appendKey(enc.prefix, key)
+	enc.nest(n, value, nil)
+}
+
+func (enc *textEncoder) AddArray(key string, value ArrayMarshaler) {
+	n := len(enc.prefix)
+	enc.prefix = enc.t.appendKey(enc.prefix, key)
+	enc.nest(n, nil, value)
+}
+
+func (enc *textEncoder) AddValue(key string, value interface{}) {
+	switch v := value.(type) {
+	case ObjectMarshaler:
+		enc.AddObject(key, v)
+	case ArrayMarshaler:
+		enc.AddArray(key, v)
+	default:
+		enc.key(key)
+		enc.t.valueAny(enc.e, value)
+		enc.end()
+	}
+}
+
+func (enc *textEncoder) AppendString(value string) {
+	enc.element()
+	enc.t.valueString(enc.e, value)
+	enc.end()
+}
+
+func (enc *textEncoder) AppendInt(value int64) {
+	enc.element()
+	enc.e.buf = strconv.AppendInt(enc.e.buf, value, 10)
+	enc.end()
+}
+
+func (enc *textEncoder) AppendUint(value uint64) {
+	enc.element()
+	enc.e.buf = strconv.AppendUint(enc.e.buf, value, 10)
+	enc.end()
+}
+
+func (enc *textEncoder) AppendFloat(value float64) {
+	enc.element()
+	enc.e.buf = strconv.AppendFloat(enc.e.buf, value, 'g', -1, 64)
+	enc.end()
+}
+
+func (enc *textEncoder) AppendBool(value bool) {
+	enc.element()
+	enc.e.buf = strconv.AppendBool(enc.e.buf, value)
+	enc.end()
+}
+
+func (enc *textEncoder) AppendDuration(value time.Duration) {
+	enc.element()
+	enc.e.buf = append(enc.e.buf, value.String()...)
+	enc.end()
+}
+
+func (enc *textEncoder) AppendTime(value time.Time) {
+	enc.element()
+	enc.e.buf = value.AppendFormat(enc.e.buf, time.RFC3339Nano)
+	enc.end()
+}
+
+func (enc *textEncoder) AppendObject(value ObjectMarshaler) {
+	n := len(enc.prefix)
+	enc.prefix = strconv.AppendInt(enc.prefix, int64(enc.index), 10)
+	enc.index++
+	enc.nest(n, value, nil)
+}
+
+func (enc *textEncoder) AppendArray(value ArrayMarshaler) {
+	n := len(enc.prefix)
+	enc.prefix = strconv.AppendInt(enc.prefix, int64(enc.index), 10)
+	enc.index++
+	enc.nest(n, nil, value)
+}
+
+func (enc *textEncoder) AppendValue(value interface{}) {
+	switch v := value.(type) {
+	case ObjectMarshaler:
+		enc.AppendObject(v)
+	case ArrayMarshaler:
+		enc.AppendArray(v)
+	default:
+		enc.element()
+		enc.t.valueAny(enc.e, value)
+		enc.end()
+	}
+}
+
+// compactEncoder encodes objects and arrays as {key=value} and [value value] for human friendly formatters.
+type compactEncoder struct {
+	buf   []byte
+	first bool // no value was written in the current object or array
+}
+
+// sep separates the value from the previous one.
+func (enc *compactEncoder) sep() {
+	if enc.first {
+		enc.first = false
+	} else {
+		enc.buf = append(enc.buf, space)
+	}
+}
+
+func (enc *compactEncoder) object(value ObjectMarshaler) {
+	enc.buf = append(enc.buf, '{')
+	enc.first = true
+	value.LogObject(enc)
+	enc.first = false
+	enc.buf = append(enc.buf, '}')
+}
+
+func (enc *compactEncoder) array(value ArrayMarshaler) {
+	enc.buf = append(enc.buf, '[')
+	enc.first = true
+	value.LogArray(enc)
+	enc.first = false
+	enc.buf = append(enc.buf, ']')
+}
+
+// string appends a value that is quoted when it contains spaces, quotes, equal signs, brackets or braces, so values
+// can be told apart from the enclosing object or array.
+func (enc *compactEncoder) string(value string) {
+	start := len(enc.buf)
+	enc.buf = append(enc.buf, value...)
+	enc.quote(start)
+}
+
+// value appends any value, objects and arrays are encoded and other values are quoted when required.
+func (enc *compactEncoder) value(value interface{}) {
+	switch v := value.(type) {
+	case ObjectMarshaler:
+		enc.object(v)
+	case ArrayMarshaler:
+		enc.array(v)
+	default:
+		start := len(enc.buf)
+		enc.buf = appendValue(enc.buf, value)
+		enc.quote(start)
+	}
+}
+
+func (enc *compactEncoder) quote(start int) {
+	if value := enc.buf[start:]; needsQuote(value) || bytes.ContainsAny(value, "{}[]") {
+		enc.buf = appendQuoted(enc.buf, start)
+	}
+}
+
+func (enc *compactEncoder) key(key string) {
+	enc.sep()
+	enc.buf = append(enc.buf, key...)
+	enc.buf = append(enc.buf, equal)
+}
+
+func (enc *compactEncoder) AddString(key, value string) {
+	enc.key(key)
+	enc.string(value)
+}
+
+func (enc *compactEncoder) AddInt(key string, value int64) {
+	enc.key(key)
+	enc.buf = strconv.AppendInt(enc.buf, value, 10)
+}
+
+func (enc *compactEncoder) AddUint(key string, value uint64) {
+	enc.key(key)
+	enc.buf = strconv.AppendUint(enc.buf, value, 10)
+}
+
+func (enc *compactEncoder) AddFloat(key string, value float64) {
+	enc.key(key)
+	enc.buf = strconv.AppendFloat(enc.buf, value, 'g', -1, 64)
+}
+
+func (enc *compactEncoder) AddBool(key string, value bool) {
+	enc.key(key)
+	enc.buf = strconv.AppendBool(enc.buf, value)
+}
+
+func (enc *compactEncoder) AddDuration(key string, value time.Duration) {
+	enc.key(key)
+	enc.buf = append(enc.buf, value.String()...)
+}
+
+func (enc *compactEncoder) AddTime(key string, value time.Time) {
+	enc.key(key)
+	enc.buf = value.AppendFormat(enc.buf, time.RFC3339Nano)
+}
+
+func (enc *compactEncoder) AddObject(key string, value ObjectMarshaler) {
+	enc.key(key)
+	enc.object(value)
+}
+
+func (enc *compactEncoder) AddArray(key string, value ArrayMarshaler) {
+	enc.key(key)
+	enc.array(value)
+}
+
+func (enc *compactEncoder) AddValue(key string, value interface{}) {
+	enc.key(key)
+	enc.value(value)
+}
+
+func (enc *compactEncoder) AppendString(value string) {
+	enc.sep()
+	enc.string(value)
+}
+
+func (enc *compactEncoder) AppendInt(value int64) {
+	enc.sep()
+	enc.buf = strconv.AppendInt(enc.buf, value, 10)
+}
+
+func (enc *compactEncoder) AppendUint(value uint64) {
+	enc.sep()
+	enc.buf = strconv.AppendUint(enc.buf, value, 10)
+}
+
+func (enc *compactEncoder) AppendFloat(value float64) {
+	enc.sep()
+	enc.buf = strconv.AppendFloat(enc.buf, value, 'g', -1, 64)
+}
+
+func (enc *compactEncoder) AppendBool(value bool) {
+	enc.sep()
+	enc.buf = strconv.AppendBool(enc.buf, value)
+}
+
+func (enc *compactEncoder) AppendDuration(value time.Duration) {
+	enc.sep()
+	enc.buf = append(enc.buf, value.String()...)
+}
+
+func (enc *compactEncoder) AppendTime(value time.Time) {
+	enc.sep()
+	enc.buf = value.AppendFormat(enc.buf, time.RFC3339Nano)
+}
+
+func (enc *compactEncoder) AppendObject(value ObjectMarshaler) {
+	enc.sep()
+	enc.object(value)
+}
+
+func (enc *compactEncoder) AppendArray(value ArrayMarshaler) {
+	enc.sep()
+	enc.array(value)
+}
+
+func (enc *compactEncoder) AppendValue(value interface{}) {
+	enc.sep()
+	enc.value(value)
+}
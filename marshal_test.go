@@ -0,0 +1,205 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+type testDebtor struct {
+	Name string
+	City string
+}
+
+func (d *testDebtor) LogObject(enc ObjectEncoder) {
+	enc.AddString("name", d.Name)
+	enc.AddString("city", d.City)
+}
+
+type testTags []string
+
+func (t testTags) LogArray(enc ArrayEncoder) {
+	for _, tag := range t {
+		enc.AppendString(tag)
+	}
+}
+
+type testCollection struct {
+	ID     int64
+	Amount float64
+	Paid   bool
+	Took   time.Duration
+	Debtor *testDebtor
+	Tags   testTags
+}
+
+func (c *testCollection) LogObject(enc ObjectEncoder) {
+	enc.AddInt("id", c.ID)
+	enc.AddFloat("amount", c.Amount)
+	enc.AddBool("paid", c.Paid)
+	enc.AddObject("debtor", c.Debtor)
+	enc.AddArray("tags", &c.Tags) // a pointer avoids allocating the interface
+	enc.AddArray("history", ArrayMarshalerFunc(func(enc ArrayEncoder) {
+		enc.AppendObject(ObjectMarshalerFunc(func(enc ObjectEncoder) {
+			enc.AddUint("attempt", 1)
+		}))
+		enc.AppendValue(nil)
+	}))
+}
+
+func newTestCollection() *testCollection {
+	return &testCollection{
+		ID:     42,
+		Amount: 12.5,
+		Paid:   true,
+		Debtor: &testDebtor{Name: "John \"JD\" Doe", City: "Ghent"},
+		Tags:   testTags{"recurring", "b2b"},
+	}
+}
+
+func TestTextFormatter_object(t *testing.T) {
+	formatter := &TextFormatter{MessageField: "msg"}
+	e := &Event{Message: "collected", Fields: []Field{F("collection", newTestCollection()), F("tags", testTags{"a b"})}}
+
+	want := `msg=collected collection.id=42 collection.amount=12.5 collection.paid=true collection.debtor.name="John \"JD\" Doe" ` +
+		`collection.debtor.city=Ghent collection.tags.0=recurring collection.tags.1=b2b collection.history.0.attempt=1 ` +
+		`collection.history.1=<nil> tags.0="a b"` + "\n"
+	formatter.Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %s\nGot: %s", want, string(e.buf))
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	formatter := NewJSONFormatter()
+	e := &Event{
+		Time:    time.Unix(1, 0),
+		Module:  "collections",
+		Level:   LevelInfo,
+		Message: "collected \"42\"\n\x1b[31m",
+		Fields: []Field{
+			F("collection", newTestCollection()),
+			F("took", 15*time.Millisecond),
+			F("ratio", math.NaN()),
+			F("password", Redacted("hunter2")),
+			F("bad", "\xff\u2028"),
+		},
+	}
+
+	want := `{"ts":1000,"logger":"collections","lvl":"info","msg":"collected \"42\"\n\u001b[31m",` +
+		`"collection":{"id":42,"amount":12.5,"paid":true,"debtor":{"name":"John \"JD\" Doe","city":"Ghent"},` +
+		`"tags":["recurring","b2b"],"history":[{"attempt":1},null]},"took":"15ms","ratio":"NaN","password":"***",` +
+		`"bad":"\ufffd\u2028"}` + "\n"
+	formatter.Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %s\nGot: %s", want, string(e.buf))
+	}
+	if !json.Valid(e.buf) {
+		t.Errorf("invalid JSON: %s", e.buf)
+	}
+}
+
+// indentedJSON is a json.Marshaler that returns indented JSON.
+type indentedJSON struct{}
+
+func (indentedJSON) MarshalJSON() ([]byte, error) {
+	return []byte("{\n  \"a\": 1\n}"), nil
+}
+
+func TestJSONFormatter_marshaler(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: &JSONFormatter{MessageField: "msg"}})
+
+	log.With(F("value", indentedJSON{})).Info("marshaled")
+	want := `{"msg":"marshaled","value":{"a":1}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestJournalFormatter_object(t *testing.T) {
+	formatter := NewJournalFormatter()
+	e := &Event{Level: LevelInfo, Message: "collected", Fields: []Field{F("collection", newTestCollection())}}
+
+	want := "info - collected collection={id=42 amount=12.5 paid=true debtor={name=\"John \\\"JD\\\" Doe\" city=Ghent} " +
+		"tags=[recurring b2b] history=[{attempt=1} <nil>]}\n"
+	formatter.Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %s\nGot: %s", want, string(e.buf))
+	}
+}
+
+func FuzzJSONFormatter(f *testing.F) {
+	f.Add("hello", "key", "value")
+	f.Add("he said \"hi\"\n", "user name", `C:\temp`)
+	f.Add("bad\xffutf8\x00", "a\"b", "\x1b[31mred\u2028")
+
+	formatter := &JSONFormatter{MessageField: "msg"}
+	f.Fuzz(func(t *testing.T, message, key, value string) {
+		e := &Event{Message: message, Fields: []Field{F(key, value)}}
+		formatter.Format(e)
+
+		var decoded map[string]string
+		if err := json.Unmarshal(e.buf, &decoded); err != nil {
+			t.Fatalf("%v: %q", err, e.buf)
+		}
+		if decoded["msg"] != validUTF8(message) && key != "msg" {
+			t.Errorf("\nWant: %q\nGot: %q", validUTF8(message), decoded["msg"])
+		}
+	})
+}
+
+func TestFormatter_objectAllocs(t *testing.T) {
+	formatters := map[string]Formatter{
+		"text": NewTextFormatter(),
+		"json": NewJSONFormatter(),
+	}
+	for name, formatter := range formatters {
+		c := newTestCollection()
+		e := &Event{buf: make([]byte, 0, 500), Message: "collected", Fields: []Field{F("collection", c)}}
+		allocs := testing.AllocsPerRun(100, func() {
+			e.buf = e.buf[:0]
+			formatter.Format(e)
+		})
+		if allocs != 0 {
+			t.Errorf("%s: expected 0 allocations but got %.1f", name, allocs)
+		}
+	}
+}
+
+func TestEvent_Clone(t *testing.T) {
+	e := &Event{Message: "collected", Fields: []Field{F("collection", newTestCollection())}}
+	NewTextFormatter().Format(e)
+	NewJSONFormatter().Format(e)
+
+	clone := e.Clone()
+	if clone.text.e != nil || clone.text.prefix != nil || clone.json.e != nil {
+		t.Errorf("expected the encoder state not to be shared with the clone")
+	}
+}
+
+func BenchmarkTextFormatter_object(b *testing.B) {
+	formatter := NewTextFormatter()
+	c := newTestCollection()
+	e := &Event{buf: make([]byte, 0, 500), Message: "collected", Fields: []Field{F("collection", c)}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e.buf = e.buf[:0]
+		formatter.Format(e)
+	}
+}
+
+func BenchmarkJSONFormatter_object(b *testing.B) {
+	formatter := NewJSONFormatter()
+	c := newTestCollection()
+	e := &Event{buf: make([]byte, 0, 500), Message: "collected", Fields: []Field{F("collection", c)}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e.buf = e.buf[:0]
+		formatter.Format(e)
+	}
+}
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Recorder is a flight recorder that keeps the most recent events in memory, including the events below the
+// level of the logger. The events are only formatted when the recorder is dumped, which happens automatically
+// when an event at or above the trigger level is logged, or on demand.
+type Recorder struct {
+	mu      sync.Mutex
+	trigger Level
+	slots   []recording
+	next    int
+	count   int
+	seq     uint64
+}
+
+// recording is an event copy that remembers the logger it was logged with.
+type recording struct {
+	Event
+	logger  *Logger
+	seq     uint64
+	written bool
+}
+
+// NewRecorder creates a flight recorder that keeps the last size events and dumps them when an event
+// at or above the trigger level is logged. A trigger level of zero disables the automatic dump.
+// A size of zero or less records nothing.
+func NewRecorder(size int, trigger Level) *Recorder {
+	if size < 0 {
+		size = 0
+	}
+	return &Recorder{
+		trigger: trigger,
+		slots:   make([]recording, size),
+	}
+}
+
+// record copies the event into the ring buffer and dumps the buffer when the event triggers the recorder.
+// The event remembers its recording, so the recording is only dumped when the event is not written.
+func (r *Recorder) record(l *Logger, e *Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.slots) == 0 {
+		return
+	}
+
+	// keep the buffers of the slot, the event and its buffers are returned to the pool
+	slot := &r.slots[r.next]
+	buf, fields, args, text := slot.buf, slot.Fields, slot.args, slot.text
+	slot.Event = *e
+	slot.buf = buf[:0]
+	slot.Fields = append(fields[:0], e.Fields...)
+	if len(e.args) > 0 {
+		slot.args = append(args[:0], e.args...)
+	} else {
+		slot.args = args[:0]
+	}
+	slot.text = text
+	slot.json = jsonEncoder{}
+	slot.logger = l
+	r.seq++
+	slot.seq = r.seq
+	slot.written = false
+
+	r.next = (r.next + 1) % len(r.slots)
+	if r.count < len(r.slots) {
+		r.count++
+	}
+
+	e.recorder = r
+	e.recorded = r.seq
+
+	if e.Level > 0 && e.Level <= r.trigger {
+		// a triggered dump is written before the triggering event, which is kept until it is written
+		r.dumpLocked(1)
+	}
+}
+
+// written marks the recording of the event as written.
+func (r *Recorder) written(e *Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.slots) == 0 {
+		return
+	}
+	if slot := &r.slots[(e.recorded-1)%uint64(len(r.slots))]; slot.seq == e.recorded {
+		slot.written = true
+	}
+}
+
+// Dump writes the recorded events that were not yet written to the writers of the loggers they were logged
+// with and clears the recorder. It returns the number of dumped events.
+func (r *Recorder) Dump() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dumpLocked(0)
+}
+
+// dumpLocked dumps the recorded events except for the most recent keep events.
+func (r *Recorder) dumpLocked(keep int) int {
+	var dumped int
+	if len(r.slots) == 0 {
+		return dumped
+	}
+	start := (r.next - r.count + len(r.slots)) % len(r.slots)
+	for i := 0; i < r.count-keep; i++ {
+		slot := &r.slots[(start+i)%len(r.slots)]
+		if !slot.written {
+			slot.logger.prepare(&slot.Event)
+			slot.logger.formatter.Format(&slot.Event)
+			slot.logger.output(slot.buf)
+			dumped++
+		}
+		clear(slot.Fields)
+		clear(slot.args)
+		slot.logger = nil
+	}
+	r.count = keep
+	return dumped
+}
+
+// DumpOnSignal dumps the recorder every time one of the signals is received, for example syscall.SIGUSR1.
+// The returned function stops listening for the signals.
+func (r *Recorder) DumpOnSignal(sig ...os.Signal) (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, sig...)
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				r.Dump()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
+
+// ServeHTTP dumps the recorder when the handler is requested and responds with the number of dumped events.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "dumped %d events\n", r.Dump())
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{
+		Writer:    &buf,
+		Formatter: NewJournalFormatter(),
+		Level:     LevelInfo,
+		Recorder:  NewRecorder(3, LevelError),
+	})
+
+	log.Debug("dropped from ring")
+	log.Debug("loading mandate")
+	log.Info("collecting")
+	log.Error("collection failed")
+	log.Debug("after dump")
+
+	want := "info - collecting\n" +
+		"debug - loading mandate\n" +
+		"error - collection failed\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestRecorder_dump(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(10, 0)
+	log := NewWithOptions(Options{Writer: &buf, Level: LevelInfo, Recorder: recorder})
+	log.ignoreExit = true
+
+	log.Trace("first")
+	log.Fatal("no automatic dump")
+	if strings.Contains(buf.String(), "first") {
+		t.Errorf("expected no dump without trigger level")
+	}
+
+	if n := recorder.Dump(); n != 1 {
+		t.Errorf("expected 1 dumped event but got %d", n)
+	}
+	if !strings.Contains(buf.String(), "msg=first") {
+		t.Errorf("expected dumped event in output, got: %s", buf.String())
+	}
+	if n := recorder.Dump(); n != 0 {
+		t.Errorf("expected recorder to be cleared after dump, got %d events", n)
+	}
+}
+
+func TestRecorder_dropped(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(10, 0)
+	log := NewWithOptions(Options{
+		Writer:    &buf,
+		Formatter: NewJournalFormatter(),
+		Level:     LevelInfo,
+		Recorder:  recorder,
+		Sampler:   NewCountSampler(time.Minute, 1, 0),
+	})
+
+	log.Info("retrying")
+	log.Info("retrying")
+	if n := recorder.Dump(); n != 1 {
+		t.Errorf("expected 1 dumped event but got %d", n)
+	}
+
+	want := "info - retrying\ninfo - retrying\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+// TestRecorder_concurrent dumps while other goroutines are logging, run with -race to detect shared buffers.
+func TestRecorder_concurrent(t *testing.T) {
+	var buf syncBuffer
+	recorder := NewRecorder(16, 0)
+	log := NewWithOptions(Options{Writer: &buf, Level: LevelInfo, Recorder: recorder})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				switch i {
+				case 0:
+					recorder.Dump()
+				case 1:
+					log.Debug("loading \"mandate\"")
+				default:
+					log.Info("collecting \"mandate\"")
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	recorder.Dump()
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if !strings.HasSuffix(line, `msg="loading \"mandate\""`) && !strings.HasSuffix(line, `msg="collecting \"mandate\""`) {
+			t.Errorf("unexpected line: %s", line)
+		}
+	}
+}
+
+func TestRecorder_ServeHTTP(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(10, 0)
+	log := NewWithOptions(Options{Writer: &buf, Level: LevelInfo, Recorder: recorder})
+	log.Debug("hello")
+
+	rec := httptest.NewRecorder()
+	recorder.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/recorder", nil))
+
+	if got := rec.Body.String(); got != "dumped 1 events\n" {
+		t.Errorf("unexpected response: %s", got)
+	}
+}
+
+func TestRecorder_empty(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(0, LevelError)
+	log := NewWithOptions(Options{Writer: &buf, Level: LevelInfo, Recorder: recorder})
+	log.Debug("hello")
+	log.Error("failed")
+
+	if dumped := recorder.Dump(); dumped != 0 {
+		t.Errorf("\nWant: %d\nGot: %d", 0, dumped)
+	}
+	rec := httptest.NewRecorder()
+	recorder.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/recorder", nil))
+	if got := rec.Body.String(); got != "dumped 0 events\n" {
+		t.Errorf("unexpected response: %s", got)
+	}
+	if NewRecorder(-1, LevelError).Dump() != 0 {
+		t.Errorf("expected a negative size to record nothing")
+	}
+}
+
+func BenchmarkRecorderDisabled(b *testing.B) {
+	logger := NewWithOptions(Options{Writer: io.Discard, Level: LevelInfo, Recorder: NewRecorder(1000, LevelError)})
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Debug(fakeMessage)
+		}
+	})
+}
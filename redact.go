@@ -0,0 +1,310 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// redactedValue replaces the values that are removed by a Detector.
+const redactedValue = "REDACTED"
+
+// Mask defines how a Detector masks the values it found.
+type Mask uint8
+
+const (
+	// MaskLast4 replaces every letter and digit except the last four by an asterisk, separators are kept.
+	MaskLast4 Mask = iota
+	// MaskHash replaces the value by the first 16 hexadecimal characters of its SHA-256 hash, which allows
+	// correlating lines without revealing the value. Low entropy values such as emails can be brute forced.
+	MaskHash
+	// MaskRemove replaces the value by REDACTED.
+	MaskRemove
+)
+
+// Detector finds sensitive values in messages and field values. When the pattern has a capturing group only
+// the first group is masked, otherwise the complete match is masked.
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Mask    Mask
+
+	// Valid optionally confirms a match, such as the checksum of an IBAN, to avoid masking look-alikes.
+	// When a match is not valid, its prefixes ending before a space are tried.
+	Valid func(match string) bool
+
+	// hint is a cheap check that the value may contain a match, to skip the regular expression.
+	hint func(value string) bool
+}
+
+// NewDetector creates a detector for a custom regular expression. It panics when the pattern is invalid.
+func NewDetector(name, pattern string, mask Mask) Detector {
+	return Detector{Name: name, Pattern: regexp.MustCompile(pattern), Mask: mask}
+}
+
+// IBANDetector detects IBANs with a valid mod-97 checksum, written with or without spaces.
+func IBANDetector() Detector {
+	return Detector{
+		Name:    "iban",
+		Pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`),
+		Mask:    MaskLast4,
+		Valid:   validIBAN,
+		hint:    ibanHint,
+	}
+}
+
+// PANDetector detects card numbers of 13 to 19 digits that pass the Luhn check, written with or without
+// spaces or hyphens.
+func PANDetector() Detector {
+	return Detector{
+		Name:    "pan",
+		Pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Mask:    MaskLast4,
+		Valid:   validLuhn,
+		hint: func(value string) bool {
+			return countDigits(value) >= 13
+		},
+	}
+}
+
+// EmailDetector detects email addresses.
+func EmailDetector() Detector {
+	d := NewDetector("email", `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, MaskHash)
+	d.hint = func(value string) bool {
+		return strings.IndexByte(value, '@') >= 0
+	}
+	return d
+}
+
+// BearerDetector detects the token of bearer authorization values.
+func BearerDetector() Detector {
+	d := NewDetector("bearer", `(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`, MaskRemove)
+	d.hint = func(value string) bool {
+		return strings.Contains(value, "earer") || strings.Contains(value, "EARER")
+	}
+	return d
+}
+
+// JWTDetector detects JSON Web Tokens.
+func JWTDetector() Detector {
+	d := NewDetector("jwt", `\beyJ[A-Za-z0-9_-]*\.eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`, MaskRemove)
+	d.hint = func(value string) bool {
+		return strings.Contains(value, "eyJ")
+	}
+	return d
+}
+
+// DefaultDetectors returns the built-in detectors for IBANs, card numbers, emails, JSON Web Tokens and bearer tokens.
+func DefaultDetectors() []Detector {
+	return []Detector{IBANDetector(), PANDetector(), EmailDetector(), JWTDetector(), BearerDetector()}
+}
+
+// Redactor masks sensitive values in the message and field values of events before they are formatted.
+// A single Redactor may be shared between multiple loggers.
+type Redactor struct {
+	detectors []Detector
+	counts    []atomic.Uint64
+}
+
+// NewRedactor creates a redactor for the detectors, it uses the DefaultDetectors when none are given.
+func NewRedactor(detectors ...Detector) *Redactor {
+	if len(detectors) == 0 {
+		detectors = DefaultDetectors()
+	}
+	return &Redactor{
+		detectors: detectors,
+		counts:    make([]atomic.Uint64, len(detectors)),
+	}
+}
+
+// Counts returns the number of values each detector masked, by the name of the detector.
+func (r *Redactor) Counts() map[string]uint64 {
+	counts := make(map[string]uint64, len(r.detectors))
+	for i, d := range r.detectors {
+		counts[d.Name] += r.counts[i].Load()
+	}
+	return counts
+}
+
+// Redact returns the value with all sensitive values masked.
+func (r *Redactor) Redact(value string) string {
+	for i := range r.detectors {
+		value = r.redactDetector(i, value)
+	}
+	return value
+}
+
+// redact masks the sensitive values in the message and field values of the event.
+// Field values that are not text are only replaced when they contained a sensitive value.
+func (r *Redactor) redact(e *Event) {
+	e.Message = r.Redact(e.Message)
+	for i, f := range e.Fields {
+		switch v := f.Value.(type) {
+		case nil, bool, float32, float64, time.Duration, time.Time:
+			// never sensitive
+		case string:
+			e.Fields[i].Value = r.Redact(v)
+		default:
+			text := string(appendValue(nil, v))
+			if redacted := r.Redact(text); redacted != text {
+				e.Fields[i].Value = redacted
+			}
+		}
+	}
+}
+
+// validPrefix returns the length of the longest valid prefix of the match that ends before a space, since
+// patterns with optional spaces may match words that follow the value. It returns zero when none is valid.
+func (d *Detector) validPrefix(match string) int {
+	for {
+		if d.Valid(match) {
+			return len(match)
+		}
+		i := strings.LastIndexByte(match, ' ')
+		if i <= 0 {
+			return 0
+		}
+		match = match[:i]
+	}
+}
+
+// redactDetector masks the values found by a single detector.
+func (r *Redactor) redactDetector(i int, value string) string {
+	d := &r.detectors[i]
+	if d.hint != nil && !d.hint(value) {
+		return value
+	}
+	matches := d.Pattern.FindAllStringSubmatchIndex(value, -1)
+	if matches == nil {
+		return value
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3] // mask the first group only
+		}
+		if d.Valid != nil {
+			if end = d.validPrefix(value[start:end]); end == 0 {
+				continue
+			}
+			end += start
+		}
+		b.WriteString(value[last:start])
+		b.WriteString(d.Mask.apply(value[start:end]))
+		last = end
+		r.counts[i].Add(1)
+	}
+	if last == 0 {
+		return value // no valid matches
+	}
+	b.WriteString(value[last:])
+	return b.String()
+}
+
+// apply masks the value.
+func (m Mask) apply(value string) string {
+	switch m {
+	case MaskLast4:
+		keep := 4
+		masked := []byte(value)
+		for i := len(masked) - 1; i >= 0; i-- {
+			if !isAlphanumeric(masked[i]) {
+				continue // keep separators
+			}
+			if keep > 0 {
+				keep--
+			} else {
+				masked[i] = '*'
+			}
+		}
+		return string(masked)
+	case MaskHash:
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	default:
+		return redactedValue
+	}
+}
+
+// validIBAN checks the length and mod-97 checksum of an IBAN, spaces are ignored.
+func validIBAN(iban string) bool {
+	iban = strings.ReplaceAll(iban, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	// move the country code and check digits to the end and convert letters to numbers, A = 10 ... Z = 35
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for i := 0; i < len(rearranged); i++ {
+		c := rearranged[i]
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// validLuhn checks the Luhn checksum of a card number, spaces and hyphens are ignored.
+func validLuhn(pan string) bool {
+	sum, n := 0, 0
+	for i := len(pan) - 1; i >= 0; i-- {
+		c := pan[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// ibanHint returns true when the value contains two upper case letters followed by two digits.
+func ibanHint(value string) bool {
+	for i := 0; i+3 < len(value); i++ {
+		if isUpper(value[i]) && isUpper(value[i+1]) && isDigit(value[i+2]) && isDigit(value[i+3]) {
+			return true
+		}
+	}
+	return false
+}
+
+func countDigits(value string) int {
+	n := 0
+	for i := 0; i < len(value); i++ {
+		if isDigit(value[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
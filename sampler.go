@@ -0,0 +1,238 @@
+package logger
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// numLevels is the number of valid logging levels.
+	numLevels = int(LevelTrace)
+
+	// sampleBuckets is the number of counters a counting sampler keeps per level.
+	sampleBuckets = 1024
+)
+
+// Sampler decides whether an event is written, which allows taming floods of similar log lines.
+// Samplers are consulted after the level check and before the event is formatted.
+type Sampler interface {
+	// Sample reports whether the event should be written.
+	Sample(e *Event) bool
+
+	// Suppressed returns the events that were dropped since the previous report and resets the counters.
+	// It returns nil as long as the report interval of the sampler has not elapsed.
+	Suppressed(now time.Time) []Suppression
+}
+
+// Suppression summarises the events a Sampler dropped for a single key.
+type Suppression struct {
+	Level Level
+	Key   string
+	Count uint64
+}
+
+// reporter keeps track of when a sampler should report its suppressed events.
+type reporter struct {
+	interval int64
+	next     atomic.Int64
+}
+
+// due returns true for exactly one caller once the report interval has elapsed.
+func (r *reporter) due(now time.Time) bool {
+	n := now.UnixNano()
+	next := r.next.Load()
+	if n < next {
+		return false
+	}
+	return r.next.CompareAndSwap(next, n+r.interval)
+}
+
+// counter tracks the number of events seen for a single bucket within a tick.
+type counter struct {
+	resetAt atomic.Int64
+	n       atomic.Uint64
+	dropped atomic.Uint64
+	key     atomic.Pointer[string]
+}
+
+// inc increments the counter and resets it when the tick has passed.
+func (c *counter) inc(now, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.n.Add(1)
+	}
+
+	c.n.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick) {
+		// another goroutine already reset the counter
+		return c.n.Add(1)
+	}
+	return 1
+}
+
+// drop records a dropped event, the key is only stored for the first drop after a report.
+func (c *counter) drop(key string) {
+	if c.dropped.Add(1) == 1 {
+		k := key // only escape to the heap when storing
+		c.key.Store(&k)
+	}
+}
+
+// CountSampler writes the first N events with the same key during a tick and every Mth event thereafter.
+type CountSampler struct {
+	reporter
+	key        func(e *Event) (string, bool)
+	tick       int64
+	first      uint64
+	thereafter uint64
+	counters   [numLevels][sampleBuckets]counter
+}
+
+// NewCountSampler creates a sampler that keys events by their level and message, or by their template when the
+// message was rendered of a template. Within every tick the first events are written after which only every
+// thereafter-th event is written.
+// When thereafter is zero all events beyond the first are dropped until the next tick.
+func NewCountSampler(tick time.Duration, first, thereafter int) *CountSampler {
+	return newCountSampler(tick, first, thereafter, func(e *Event) (string, bool) {
+		if e.Template != "" {
+			return e.Template, true
+		}
+		return e.Message, true
+	})
+}
+
+// NewFieldSampler creates a sampler that keys events by their level and the value of the named field.
+// Events without the field are never dropped. See NewCountSampler for the meaning of the other arguments.
+func NewFieldSampler(field string, tick time.Duration, first, thereafter int) *CountSampler {
+	return newCountSampler(tick, first, thereafter, func(e *Event) (string, bool) {
+		for _, f := range e.Fields {
+			if f.Key != field {
+				continue
+			}
+			if v, ok := f.Value.(string); ok {
+				return v, true
+			}
+			return string(appendValue(nil, f.Value)), true
+		}
+		return "", false
+	})
+}
+
+func newCountSampler(tick time.Duration, first, thereafter int, key func(e *Event) (string, bool)) *CountSampler {
+	s := &CountSampler{
+		key:        key,
+		tick:       int64(tick),
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+	}
+	s.interval = int64(tick)
+	return s
+}
+
+// Sample implements the Sampler interface.
+func (s *CountSampler) Sample(e *Event) bool {
+	if e.Level < LevelFatal || e.Level > LevelTrace {
+		return true
+	}
+	key, ok := s.key(e)
+	if !ok {
+		return true
+	}
+
+	c := &s.counters[e.Level-1][fnv32a(key)%sampleBuckets]
+	n := c.inc(e.Time.UnixNano(), s.tick)
+	if n <= s.first || (s.thereafter > 0 && (n-s.first)%s.thereafter == 0) {
+		return true
+	}
+	c.drop(key)
+	return false
+}
+
+// Suppressed implements the Sampler interface.
+func (s *CountSampler) Suppressed(now time.Time) []Suppression {
+	if !s.due(now) {
+		return nil
+	}
+
+	var suppressed []Suppression
+	for i := range s.counters {
+		for j := range s.counters[i] {
+			c := &s.counters[i][j]
+			if n := c.dropped.Swap(0); n > 0 {
+				var key string
+				if k := c.key.Load(); k != nil {
+					key = *k
+				}
+				suppressed = append(suppressed, Suppression{Level: Level(i + 1), Key: key, Count: n})
+			}
+		}
+	}
+	return suppressed
+}
+
+// RandomSampler writes events with a fixed probability per level.
+type RandomSampler struct {
+	reporter
+	rates   [numLevels]float64
+	dropped [numLevels]atomic.Uint64
+}
+
+// NewRandomSampler creates a sampler that writes events with the probability assigned to their level,
+// where 1 writes every event and 0 drops every event. Levels without a rate are always written.
+// The suppressed events are reported every interval.
+func NewRandomSampler(rates map[Level]float64, interval time.Duration) *RandomSampler {
+	s := &RandomSampler{}
+	for i := range s.rates {
+		s.rates[i] = 1
+	}
+	for lvl, rate := range rates {
+		if lvl >= LevelFatal && lvl <= LevelTrace {
+			s.rates[lvl-1] = rate
+		}
+	}
+	s.interval = int64(interval)
+	return s
+}
+
+// Sample implements the Sampler interface.
+func (s *RandomSampler) Sample(e *Event) bool {
+	if e.Level < LevelFatal || e.Level > LevelTrace {
+		return true
+	}
+	rate := s.rates[e.Level-1]
+	if rate >= 1 || (rate > 0 && rand.Float64() < rate) {
+		return true
+	}
+	s.dropped[e.Level-1].Add(1)
+	return false
+}
+
+// Suppressed implements the Sampler interface.
+func (s *RandomSampler) Suppressed(now time.Time) []Suppression {
+	if !s.due(now) {
+		return nil
+	}
+
+	var suppressed []Suppression
+	for i := range s.dropped {
+		if n := s.dropped[i].Swap(0); n > 0 {
+			suppressed = append(suppressed, Suppression{Level: Level(i + 1), Count: n})
+		}
+	}
+	return suppressed
+}
+
+// fnv32a hashes the key using the FNV-1a algorithm without allocating.
+func fnv32a(key string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= prime32
+	}
+	return hash
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCountSampler(t *testing.T) {
+	sampler := NewCountSampler(time.Minute, 2, 3)
+	now := time.Unix(100, 0)
+
+	var kept int
+	for i := 0; i < 10; i++ {
+		if sampler.Sample(&Event{Time: now, Level: LevelError, Message: "connection refused"}) {
+			kept++
+		}
+	}
+
+	// first 2, then the 5th and 8th event
+	if kept != 4 {
+		t.Errorf("expected 4 events to be kept but got %d", kept)
+	}
+
+	// other messages are counted separately
+	if !sampler.Sample(&Event{Time: now, Level: LevelError, Message: "timeout"}) {
+		t.Errorf("expected first event of other message to be kept")
+	}
+
+	// a new tick resets the counters
+	if !sampler.Sample(&Event{Time: now.Add(time.Minute), Level: LevelError, Message: "connection refused"}) {
+		t.Errorf("expected event to be kept after tick")
+	}
+}
+
+func TestCountSampler_suppressed(t *testing.T) {
+	sampler := NewCountSampler(time.Minute, 1, 0)
+	now := time.Unix(100, 0)
+
+	// first report is due immediately but nothing is suppressed
+	if got := sampler.Suppressed(now); got != nil {
+		t.Errorf("expected no suppressions but got %v", got)
+	}
+
+	for i := 0; i < 5; i++ {
+		sampler.Sample(&Event{Time: now, Level: LevelWarning, Message: "retrying"})
+	}
+
+	if got := sampler.Suppressed(now.Add(time.Second)); got != nil {
+		t.Errorf("expected no report before interval but got %v", got)
+	}
+
+	got := sampler.Suppressed(now.Add(time.Minute))
+	if len(got) != 1 {
+		t.Fatalf("expected 1 suppression but got %d", len(got))
+	}
+	want := Suppression{Level: LevelWarning, Key: "retrying", Count: 4}
+	if got[0] != want {
+		t.Errorf("\nWant: %v\nGot: %v", want, got[0])
+	}
+}
+
+func TestFieldSampler(t *testing.T) {
+	sampler := NewFieldSampler("customer", time.Minute, 1, 0)
+	now := time.Unix(100, 0)
+
+	e := func(fields ...Field) *Event {
+		return &Event{Time: now, Level: LevelInfo, Message: "mandate signed", Fields: fields}
+	}
+
+	if !sampler.Sample(e(F("customer", 1))) {
+		t.Errorf("expected first event of customer 1 to be kept")
+	}
+	if sampler.Sample(e(F("customer", 1))) {
+		t.Errorf("expected second event of customer 1 to be dropped")
+	}
+	if !sampler.Sample(e(F("customer", 2))) {
+		t.Errorf("expected first event of customer 2 to be kept")
+	}
+	if !sampler.Sample(e()) {
+		t.Errorf("expected event without field to be kept")
+	}
+}
+
+func TestRandomSampler(t *testing.T) {
+	sampler := NewRandomSampler(map[Level]float64{LevelDebug: 0}, time.Minute)
+	now := time.Unix(100, 0)
+
+	if sampler.Sample(&Event{Time: now, Level: LevelDebug}) {
+		t.Errorf("expected debug event to be dropped")
+	}
+	if !sampler.Sample(&Event{Time: now, Level: LevelInfo}) {
+		t.Errorf("expected info event to be kept")
+	}
+
+	got := sampler.Suppressed(now)
+	if len(got) != 1 || got[0].Level != LevelDebug || got[0].Count != 1 {
+		t.Errorf("unexpected suppressions: %v", got)
+	}
+}
+
+func TestLoggerSampler(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Sampler: NewCountSampler(time.Hour, 1, 0)})
+
+	for i := 0; i < 3; i++ {
+		log.Error("flood")
+	}
+
+	lines := strings.Count(buf.String(), "\n")
+	if lines != 1 {
+		t.Errorf("expected 1 line but got %d", lines)
+	}
+}
+
+func BenchmarkCountSampler(b *testing.B) {
+	logger := NewWithOptions(Options{Writer: io.Discard, Sampler: NewCountSampler(time.Second, 1<<62, 0)})
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info(fakeMessage)
+		}
+	})
+}
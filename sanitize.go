@@ -0,0 +1,140 @@
+package logger
+
+import "unicode/utf8"
+
+// SanitizeMode defines how a formatter neutralizes characters in messages, modules and fields that could forge
+// log lines or control a terminal: CR, LF and other C0 and C1 control characters, ANSI CSI sequences and invalid
+// UTF-8. The zero value escapes them, which makes formatters secure by default.
+type SanitizeMode uint8
+
+const (
+	// SanitizeEscape writes control characters as \n, \r, \t or \u00XX and invalid UTF-8 as \xXX.
+	SanitizeEscape SanitizeMode = iota
+	// SanitizeReplace replaces every control character, CSI sequence and invalid byte by the replacement character.
+	SanitizeReplace
+	// SanitizeStrip removes every control character, CSI sequence and invalid byte.
+	SanitizeStrip
+	// SanitizeNone writes the values as is, it should only be used for trusted values.
+	SanitizeNone
+
+	// sanitizeHex writes every byte of control characters and invalid UTF-8 as \xXX like Apache does in access logs.
+	sanitizeHex
+)
+
+const (
+	esc         = 0x1b
+	c1CSI       = 0x9b
+	replacement = "�"
+)
+
+// AppendSanitized appends the value to dst, sanitized according to the mode.
+func (m SanitizeMode) AppendSanitized(dst []byte, value string) []byte {
+	start := len(dst)
+	dst = append(dst, value...)
+	return m.sanitize(dst, start)
+}
+
+// sanitize sanitizes the bytes of buf from start in place.
+func (m SanitizeMode) sanitize(buf []byte, start int) []byte {
+	if m == SanitizeNone || isClean(buf[start:]) {
+		return buf // fast path -> printable ascii or valid UTF-8 without control characters
+	}
+
+	end := len(buf)
+	raw := buf[start:end] // stays valid when appending reallocates the buffer
+	safe := 0             // start of the bytes that are not yet appended
+	for i := 0; i < len(raw); {
+		if c := raw[i]; c >= ' ' && c < 0x7f {
+			i++
+			continue // printable ascii is appended in bulk
+		}
+
+		r, size := utf8.DecodeRune(raw[i:])
+		invalid := r == utf8.RuneError && size == 1
+		if !invalid && !isControl(r) {
+			i += size
+			continue
+		}
+
+		buf = append(buf, raw[safe:i]...)
+		if r == esc || r == c1CSI {
+			if n := csiLength(raw[i:], size); n > 0 && (m == SanitizeReplace || m == SanitizeStrip) {
+				size = n // replace or strip the complete sequence
+			}
+		}
+		switch m {
+		case SanitizeEscape:
+			buf = appendEscaped(buf, r, raw[i], invalid)
+		case SanitizeReplace:
+			buf = append(buf, replacement...)
+		case sanitizeHex:
+			for _, b := range raw[i : i+size] {
+				buf = append(buf, '\\', 'x', hexDigits[b>>4], hexDigits[b&0xf])
+			}
+		}
+		i += size
+		safe = i
+	}
+	buf = append(buf, raw[safe:]...)
+
+	// move the sanitized value over the raw value
+	n := copy(buf[start:], buf[end:])
+	return buf[:start+n]
+}
+
+// appendEscaped appends the escaped form of a control character or invalid byte.
+func appendEscaped(buf []byte, r rune, b byte, invalid bool) []byte {
+	switch {
+	case invalid:
+		return append(buf, '\\', 'x', hexDigits[b>>4], hexDigits[b&0xf])
+	case r == '\n':
+		return append(buf, '\\', 'n')
+	case r == '\r':
+		return append(buf, '\\', 'r')
+	case r == '\t':
+		return append(buf, '\\', 't')
+	default:
+		return append(buf, '\\', 'u', '0', '0', hexDigits[r>>4], hexDigits[r&0xf])
+	}
+}
+
+// csiLength returns the length of the ANSI CSI sequence at the start of p, or zero when p does not start with one.
+// The introducer is either ESC [ or the C1 CSI character of the given size.
+func csiLength(p []byte, size int) int {
+	i := size
+	if p[0] == esc {
+		if len(p) < 2 || p[1] != '[' {
+			return 0
+		}
+		i = 2
+	}
+	for i < len(p) && p[i] >= 0x20 && p[i] <= 0x3f {
+		i++ // parameter and intermediate bytes
+	}
+	if i < len(p) && p[i] >= 0x40 && p[i] <= 0x7e {
+		i++ // final byte
+	}
+	return i
+}
+
+// isClean returns true when p is valid UTF-8 without control characters.
+func isClean(p []byte) bool {
+	for i := 0; i < len(p); {
+		c := p[i]
+		if c >= ' ' && c < 0x7f {
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(p[i:])
+		if (r == utf8.RuneError && size == 1) || isControl(r) {
+			return false
+		}
+		i += size
+	}
+	return true
+}
+
+// isControl returns true for the C0 and C1 control characters and DEL.
+func isControl(r rune) bool {
+	return r < ' ' || (r >= 0x7f && r <= 0x9f)
+}
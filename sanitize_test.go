@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		escape  string
+		replace string
+		strip   string
+	}{
+		{value: "clean ascii", escape: "clean ascii", replace: "clean ascii", strip: "clean ascii"},
+		{value: "héllo wörld", escape: "héllo wörld", replace: "héllo wörld", strip: "héllo wörld"},
+		{value: "a\nb\r\tc", escape: `a\nb\r\tc`, replace: "a�b��c", strip: "abc"},
+		{value: "bell\x07del\x7f", escape: `bell\u0007del\u007f`, replace: "bell�del�", strip: "belldel"},
+		{value: "c1\u0085x", escape: `c1\u0085x`, replace: "c1�x", strip: "c1x"},
+		{value: "\x1b[31;1mred\x1b[0m", escape: `\u001b[31;1mred\u001b[0m`, replace: "�red�", strip: "red"},
+		{value: "\u009b2Jclear", escape: `\u009b2Jclear`, replace: "�clear", strip: "clear"},
+		{value: "esc\x1b]0;title", escape: `esc\u001b]0;title`, replace: "esc�]0;title", strip: "esc]0;title"},
+		{value: "bad\xffutf8", escape: `bad\xffutf8`, replace: "bad�utf8", strip: "badutf8"},
+	}
+
+	for _, tt := range tests {
+		for mode, want := range map[SanitizeMode]string{SanitizeEscape: tt.escape, SanitizeReplace: tt.replace, SanitizeStrip: tt.strip, SanitizeNone: tt.value} {
+			got := string(mode.AppendSanitized([]byte("prefix "), tt.value))
+			if got != "prefix "+want {
+				t.Errorf("mode %d\nWant: %q\nGot: %q", mode, "prefix "+want, got)
+			}
+		}
+	}
+}
+
+func TestJournalFormatter_injection(t *testing.T) {
+	formatter := NewJournalFormatter()
+	e := &Event{
+		Level:   LevelInfo,
+		Module:  "webhook",
+		Message: "holder John\ninfo - forged line",
+		Fields:  []Field{F("name", "\x1b[2Jcleared")},
+	}
+
+	want := `[webhook] info - holder John\ninfo - forged line name=\u001b[2Jcleared` + "\n"
+	formatter.Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %s\nGot: %s", want, string(e.buf))
+	}
+}
+
+func TestPrettyFormatter_injection(t *testing.T) {
+	formatter := &PrettyFormatter{TimeFormat: "15:04", Sanitize: SanitizeStrip}
+	e := &Event{
+		Level:   LevelInfo,
+		Module:  "webhook",
+		Message: "holder \x1b[31mJohn\r\n",
+	}
+
+	want := "\x1b[90m00:00\x1b[0m \x1b[32mINF\x1b[0m \x1b[90m[\x1b[0m\x1b[37mwebhook\x1b[0m\x1b[90m]\x1b[0m holder John\n"
+	formatter.Format(e)
+	if want != string(e.buf) {
+		t.Errorf("\nWant: %q\nGot: %q", want, string(e.buf))
+	}
+}
+
+func TestCommonLogFormatter_injection(t *testing.T) {
+	formatter := NewCommonLogFormatter()
+	e := &Event{
+		Message: "started\n127.0.0.1 - - forged",
+	}
+
+	formatter.Format(e)
+	want := `"started\x0a127.0.0.1 - - forged"`
+	if got := string(e.buf); !strings.Contains(got, want) {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestCommonLogFormatter_sanitize(t *testing.T) {
+	tests := []struct {
+		mode SanitizeMode
+		want string
+	}{
+		{SanitizeEscape, `"x\xc2\x9b31m \"quoted\" bad\xff"`},
+		{SanitizeReplace, `"x� \"quoted\" bad�"`},
+		{SanitizeStrip, `"x \"quoted\" bad"`},
+		{SanitizeNone, "\"x\u009b31m \\\"quoted\\\" bad\xff\""},
+	}
+
+	for _, tt := range tests {
+		formatter := &CommonLogFormatter{Sanitize: tt.mode}
+		e := &Event{Message: "x\u009b31m \"quoted\" bad\xff"}
+		formatter.Format(e)
+		if got := string(e.buf); !strings.Contains(got, tt.want) {
+			t.Errorf("mode %d\nWant: %q\nGot: %q", tt.mode, tt.want, got)
+		}
+	}
+}
+
+func BenchmarkJournalFormatter_sanitize(b *testing.B) {
+	formatter := NewJournalFormatter()
+	e := &Event{
+		buf:     make([]byte, 0, 500),
+		Module:  "main",
+		Level:   LevelInfo,
+		Message: "Hello world!\n\x1b[31mforged",
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e.buf = e.buf[:0]
+		formatter.Format(e)
+	}
+}
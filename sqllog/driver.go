@@ -0,0 +1,262 @@
+package sqllog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+)
+
+// loggedDriver wraps a driver.Driver.
+type loggedDriver struct {
+	driver.Driver
+	*logging
+}
+
+func (d *loggedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &loggedConn{Conn: conn, logging: d.logging}, nil
+}
+
+// OpenConnector implements driver.DriverContext when the wrapped driver does.
+func (d *loggedDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.Driver.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &loggedConnector{Connector: c, logging: d.logging, driver: d}, nil
+	}
+	return &loggedConnector{Connector: dsnConnector{name: name, driver: d.Driver}, logging: d.logging, driver: d}, nil
+}
+
+// dsnConnector is a connector for drivers that do not implement driver.DriverContext.
+type dsnConnector struct {
+	name   string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// loggedConnector wraps a driver.Connector.
+type loggedConnector struct {
+	driver.Connector
+	*logging
+	driver driver.Driver
+}
+
+func (c *loggedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &loggedConn{Conn: conn, logging: c.logging}, nil
+}
+
+func (c *loggedConnector) Driver() driver.Driver {
+	if c.driver != nil {
+		return c.driver
+	}
+	return &loggedDriver{Driver: c.Connector.Driver(), logging: c.logging}
+}
+
+// loggedConn wraps a driver.Conn.
+type loggedConn struct {
+	driver.Conn
+	*logging
+}
+
+func (c *loggedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *loggedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := c.now()
+	var stmt driver.Stmt
+	var err error
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	c.log(ctx, "sql prepare", query, nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &loggedStmt{Stmt: stmt, logging: c.logging, query: query}, nil
+}
+
+func (c *loggedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *loggedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := c.now()
+	var tx driver.Tx
+	var err error
+	if bc, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = bc.BeginTx(ctx, opts)
+	} else if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		// the same checks as database/sql, which are skipped because the wrapper implements driver.ConnBeginTx
+		err = errors.New("sql: driver does not support non-default isolation level")
+	} else if opts.ReadOnly {
+		err = errors.New("sql: driver does not support read-only transactions")
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+	c.log(ctx, "sql begin", "", nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &loggedTx{Tx: tx, logging: c.logging, ctx: ctx}, nil
+}
+
+func (c *loggedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := c.now()
+	var res driver.Result
+	var err error
+	switch ec := c.Conn.(type) {
+	case driver.ExecerContext:
+		res, err = ec.ExecContext(ctx, query, args)
+	case driver.Execer:
+		var values []driver.Value
+		if values, err = toValues(args); err == nil {
+			res, err = ec.Exec(query, values)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+	c.log(ctx, "sql exec", query, args, start, err, rowsAffected(res)...)
+	return res, err
+}
+
+func (c *loggedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := c.now()
+	var rows driver.Rows
+	var err error
+	switch qc := c.Conn.(type) {
+	case driver.QueryerContext:
+		rows, err = qc.QueryContext(ctx, query, args)
+	case driver.Queryer:
+		var values []driver.Value
+		if values, err = toValues(args); err == nil {
+			rows, err = qc.Query(query, values)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+	c.log(ctx, "sql query", query, args, start, err)
+	return rows, err
+}
+
+func (c *loggedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *loggedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *loggedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *loggedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// loggedStmt wraps a driver.Stmt.
+type loggedStmt struct {
+	driver.Stmt
+	*logging
+	query string
+}
+
+func (s *loggedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), toNamedValues(args))
+}
+
+func (s *loggedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), toNamedValues(args))
+}
+
+func (s *loggedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := s.now()
+	var res driver.Result
+	var err error
+	if ec, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = ec.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = toValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	s.log(ctx, "sql exec", s.query, args, start, err, rowsAffected(res)...)
+	return res, err
+}
+
+func (s *loggedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := s.now()
+	var rows driver.Rows
+	var err error
+	if qc, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = qc.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = toValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	s.log(ctx, "sql query", s.query, args, start, err)
+	return rows, err
+}
+
+func (s *loggedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// loggedTx wraps a driver.Tx.
+type loggedTx struct {
+	driver.Tx
+	*logging
+	ctx context.Context
+}
+
+func (t *loggedTx) Commit() error {
+	start := t.now()
+	err := t.Tx.Commit()
+	t.log(t.ctx, "sql commit", "", nil, start, err)
+	return err
+}
+
+func (t *loggedTx) Rollback() error {
+	start := t.now()
+	err := t.Tx.Rollback()
+	t.log(t.ctx, "sql rollback", "", nil, start, err)
+	return err
+}
@@ -0,0 +1,191 @@
+// Package sqllog wraps database/sql drivers to log queries, statements and transactions through the logger.
+package sqllog
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+// Field names of a logged statement.
+const (
+	FieldQuery        = "query"
+	FieldArgs         = "args"
+	FieldDuration     = "duration"
+	FieldRowsAffected = "rows_affected"
+	FieldSource       = "source"
+	FieldError        = "error"
+)
+
+// redacted replaces the values of redacted arguments.
+const redacted = "REDACTED"
+
+// Options configures the logging of a wrapped driver.
+type Options struct {
+	// Level is the level at which statements are logged. Defaults to LevelDebug.
+	Level logger.Level
+
+	// SlowThreshold logs statements that take longer than the threshold at warning level.
+	SlowThreshold time.Duration
+
+	// LogArgs logs the arguments of statements, after passing them through RedactArg.
+	LogArgs bool
+
+	// RedactArg returns the value to log for an argument. Defaults to redacting all string and []byte arguments.
+	RedactArg func(arg driver.NamedValue) interface{}
+}
+
+// logging holds the shared state of every wrapped connection, statement and transaction.
+type logging struct {
+	l    *logger.Logger
+	opts Options
+}
+
+// Wrap returns a driver that logs every query, exec, prepare, begin, commit, rollback and error of the driver.
+// Register the returned driver with sql.Register to use it.
+func Wrap(d driver.Driver, l *logger.Logger, opts Options) driver.Driver {
+	return &loggedDriver{Driver: d, logging: newLogging(l, opts)}
+}
+
+// WrapConnector returns a connector that logs every statement of the connections it creates, see Wrap.
+// Open the database using sql.OpenDB.
+func WrapConnector(c driver.Connector, l *logger.Logger, opts Options) driver.Connector {
+	return &loggedConnector{Connector: c, logging: newLogging(l, opts)}
+}
+
+func newLogging(l *logger.Logger, opts Options) *logging {
+	if opts.Level <= 0 {
+		opts.Level = logger.LevelDebug
+	}
+	if opts.RedactArg == nil {
+		opts.RedactArg = redactArg
+	}
+	return &logging{l: l, opts: opts}
+}
+
+// now returns the current time of the clock of the logger.
+func (g *logging) now() time.Time {
+	return g.l.Clock().Now()
+}
+
+// log writes a statement line, the level depends on the error and the duration of the statement.
+func (g *logging) log(ctx context.Context, message, query string, args []driver.NamedValue, start time.Time, err error, fields ...logger.Field) {
+	if errors.Is(err, driver.ErrSkip) {
+		return // not an error -> database/sql falls back to another method
+	}
+
+	duration := g.now().Sub(start)
+	lvl := g.opts.Level
+	switch {
+	case err != nil:
+		lvl = logger.LevelError
+	case g.opts.SlowThreshold > 0 && duration > g.opts.SlowThreshold:
+		lvl = logger.LevelWarning
+	}
+
+	l := g.l
+	if ctx != nil {
+		l = l.WithContext(ctx)
+	}
+	if !l.Enabled(lvl) {
+		return
+	}
+
+	if query != "" {
+		fields = append(fields, logger.F(FieldQuery, query))
+	}
+	if g.opts.LogArgs && len(args) > 0 {
+		fields = append(fields, logger.F(FieldArgs, g.args(args)))
+	}
+	fields = append(fields, logger.F(FieldDuration, duration), logger.F(FieldSource, source()))
+	if err != nil {
+		fields = append(fields, logger.F(FieldError, err))
+	}
+	l.With(fields...).Log(lvl, message)
+}
+
+// args returns the redacted arguments as a single value.
+func (g *logging) args(args []driver.NamedValue) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, arg := range args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if arg.Name != "" {
+			b.WriteString(arg.Name)
+			b.WriteByte('=')
+		}
+		b.WriteString(fmt.Sprint(g.opts.RedactArg(arg)))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// redactArg redacts all string and []byte arguments.
+func redactArg(arg driver.NamedValue) interface{} {
+	switch arg.Value.(type) {
+	case string, []byte:
+		return redacted
+	default:
+		return arg.Value
+	}
+}
+
+// source returns the location of the code that called into database/sql.
+func source() string {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		internal := strings.HasPrefix(frame.Function, "database/sql.") ||
+			(strings.Contains(frame.Function, "/sqllog.") && !strings.HasSuffix(frame.File, "_test.go"))
+		if !internal {
+			return path.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// toNamedValues converts the deprecated positional arguments.
+func toNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+// toValues converts named arguments to the deprecated positional arguments.
+func toValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sqllog: driver does not support named arguments")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// rowsAffected returns the rows affected field when the result reports it.
+func rowsAffected(res driver.Result) []logger.Field {
+	if res == nil {
+		return nil
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		return []logger.Field{logger.F(FieldRowsAffected, n)}
+	}
+	return nil
+}
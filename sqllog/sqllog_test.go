@@ -0,0 +1,219 @@
+package sqllog
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twikey/go-logger"
+)
+
+// fakeDriver is a minimal in-memory driver. Its connections support ExecerContext but not QueryerContext,
+// so queries are sent through a prepared statement.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if strings.Contains(query, "invalid") {
+		return nil, errors.New("syntax error")
+	}
+	return &fakeStmt{query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "slow") {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return driver.RowsAffected(2), nil
+}
+
+type fakeStmt struct {
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next([]driver.Value) error {
+	return io.EOF
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return errors.New("already committed")
+}
+
+var (
+	registerOnce sync.Once
+	wrapped      bytes.Buffer // output of the registered driver
+)
+
+// open opens a database using the wrapped fake driver, logging to the buffer.
+func open(t *testing.T, buf *bytes.Buffer, opts Options) *sql.DB {
+	t.Helper()
+	l := logger.NewWithOptions(logger.Options{Writer: buf, Level: logger.LevelTrace})
+	db := sql.OpenDB(WrapConnector(dsnConnector{driver: fakeDriver{}}, l, opts))
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestWrap(t *testing.T) {
+	registerOnce.Do(func() {
+		l := logger.NewWithOptions(logger.Options{Writer: &wrapped, Level: logger.LevelDebug})
+		sql.Register("sqllog-fake", Wrap(fakeDriver{}, l, Options{}))
+	})
+	wrapped.Reset()
+
+	db, err := sql.Open("sqllog-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("DELETE FROM mandates"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := wrapped.String()
+	for _, want := range []string{`lvl=debug msg="sql exec"`, `query="DELETE FROM mandates"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output: %s", want, got)
+		}
+	}
+}
+
+func TestBeginTx_unsupportedOptions(t *testing.T) {
+	var buf bytes.Buffer
+	db := open(t, &buf, Options{})
+
+	for _, opts := range []*sql.TxOptions{{ReadOnly: true}, {Isolation: sql.LevelSerializable}} {
+		if tx, err := db.BeginTx(context.Background(), opts); err == nil {
+			_ = tx.Rollback()
+			t.Errorf("expected an error for unsupported transaction options %+v", *opts)
+		}
+	}
+}
+
+func TestExec(t *testing.T) {
+	var buf bytes.Buffer
+	db := open(t, &buf, Options{LogArgs: true})
+
+	if _, err := db.Exec("UPDATE mandates SET iban = ? WHERE id = ?", "BE68539007547034", 42); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{"lvl=debug", "msg=\"sql exec\"", "rows_affected=2", "args=\"[REDACTED, 42]\"", "source=sqllog_test.go:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output: %s", want, got)
+		}
+	}
+	if strings.Contains(got, "BE68539007547034") {
+		t.Errorf("expected argument to be redacted: %s", got)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	var buf bytes.Buffer
+	db := open(t, &buf, Options{})
+
+	rows, err := db.Query("SELECT id FROM mandates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = rows.Close()
+
+	got := buf.String()
+	if !strings.Contains(got, "msg=\"sql prepare\"") || !strings.Contains(got, "msg=\"sql query\"") {
+		t.Errorf("expected prepare and query lines: %s", got)
+	}
+	if strings.Contains(got, "args=") {
+		t.Errorf("expected no arguments to be logged: %s", got)
+	}
+}
+
+func TestSlowAndErrors(t *testing.T) {
+	var buf bytes.Buffer
+	db := open(t, &buf, Options{SlowThreshold: time.Millisecond})
+
+	if _, err := db.Exec("SELECT slow()"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Query("SELECT invalid"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "lvl=warn msg=\"sql exec\"") {
+		t.Errorf("expected slow exec at warning level: %s", got)
+	}
+	if !strings.Contains(got, "lvl=error msg=\"sql prepare\"") || !strings.Contains(got, "error=\"syntax error\"") {
+		t.Errorf("expected failed prepare at error level: %s", got)
+	}
+}
+
+func TestTx(t *testing.T) {
+	var buf bytes.Buffer
+	db := open(t, &buf, Options{})
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "msg=\"sql begin\"") || !strings.Contains(got, "msg=\"sql commit\"") {
+		t.Errorf("expected begin and commit lines: %s", got)
+	}
+}
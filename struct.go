@@ -0,0 +1,261 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// mask is written in place of secret and redacted values.
+const mask = "***"
+
+// structPlans caches the structPlan per struct type.
+var structPlans sync.Map
+
+// structPlan describes which fields of a struct type are logged and how, nested structs are flattened.
+type structPlan struct {
+	fields []fieldPlan
+	keys   sync.Map // prefix -> []string with the prefixed names of the fields
+}
+
+// fieldPlan describes how a single struct field is logged.
+type fieldPlan struct {
+	index     []int // path to the field through nested and embedded structs
+	name      string
+	omitempty bool
+	redact    bool
+}
+
+// Struct returns the exported fields of a struct, or a pointer to a struct, as fields with keys prefixed by the
+// given key and a dot. The logged fields are controlled with the log tag:
+//
+//	ID     string `log:"id"`              // logged as key.id
+//	Email  string `log:"email,omitempty"` // skipped when empty
+//	IBAN   string `log:"iban,redact"`     // logged as ***
+//	Secret string `log:"-"`               // never logged
+//
+// Fields without a tag are logged under their snake cased name, nested structs are flattened with dotted keys and
+// embedded structs are flattened without a prefix. Types implementing fmt.Stringer or error are logged as a value.
+// The fields of every type are determined once and cached.
+func Struct(key string, v interface{}) []Field {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return []Field{F(key, nil)}
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || isLeaf(rv.Type()) {
+		return []Field{F(key, v)}
+	}
+
+	p := planOf(rv.Type())
+	keys := p.keysOf(key)
+	fields := make([]Field, 0, len(p.fields))
+	for i := range p.fields {
+		f := &p.fields[i]
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok || (f.omitempty && fv.IsZero()) {
+			continue
+		}
+		if f.redact {
+			fields = append(fields, F(keys[i], mask))
+		} else {
+			fields = append(fields, F(keys[i], fv.Interface()))
+		}
+	}
+	return fields
+}
+
+// keysOf returns the names of the fields prefixed by the key and a dot.
+func (p *structPlan) keysOf(key string) []string {
+	if keys, ok := p.keys.Load(key); ok {
+		return keys.([]string)
+	}
+	keys := make([]string, len(p.fields))
+	for i, f := range p.fields {
+		keys[i] = f.name
+		if key != "" {
+			keys[i] = key + "." + f.name
+		}
+	}
+	p.keys.Store(key, keys)
+	return keys
+}
+
+// planOf returns the cached plan of the struct type, or creates it.
+func planOf(t reflect.Type) *structPlan {
+	if p, ok := structPlans.Load(t); ok {
+		return p.(*structPlan)
+	}
+	p := &structPlan{fields: planFields(t, nil, "", map[reflect.Type]bool{})}
+	actual, _ := structPlans.LoadOrStore(t, p)
+	return actual.(*structPlan)
+}
+
+// planFields returns the logged fields of the struct type, index is the path to the struct and prefix the dotted
+// name of the struct. Recursive types are only flattened until the type repeats.
+func planFields(t reflect.Type, index []int, prefix string, seen map[reflect.Type]bool) []fieldPlan {
+	seen[t] = true
+	defer delete(seen, t)
+
+	var fields []fieldPlan
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("log")
+		if tag == "-" || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+
+		f := fieldPlan{index: append(index[:len(index):len(index)], i)}
+		name, options, _ := strings.Cut(tag, ",")
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "omitempty":
+				f.omitempty = true
+			case "redact":
+				f.redact = true
+			}
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		flatten := ft.Kind() == reflect.Struct && !isLeaf(ft) && !seen[ft] && !f.redact
+		if sf.Anonymous && name == "" && flatten {
+			// embedded struct -> promote its fields
+			fields = append(fields, planFields(ft, f.index, prefix, seen)...)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = snakeCase(sf.Name)
+		}
+		f.name = prefix + name
+		if flatten {
+			fields = append(fields, planFields(ft, f.index, f.name+".", seen)...)
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// fieldByIndex returns the nested field without panicking on nil embedded pointers.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		for rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(i)
+	}
+	return rv, true
+}
+
+var (
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// isLeaf returns true for struct types that are logged as a single value.
+func isLeaf(t reflect.Type) bool {
+	for _, it := range []reflect.Type{stringerType, errorType} {
+		if t.Implements(it) || reflect.PointerTo(t).Implements(it) {
+			return true
+		}
+	}
+	return t.PkgPath() == "time"
+}
+
+// snakeCase converts a Go field name such as CustomerID to customer_id.
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Secret holds a value that is never logged, it renders as *** in every formatter and through the fmt package.
+type Secret[T interface{}] struct {
+	value T
+}
+
+// NewSecret wraps the value in a Secret.
+func NewSecret[T interface{}](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Value returns the wrapped value.
+func (s Secret[T]) Value() T {
+	return s.value
+}
+
+// String implements the fmt.Stringer interface.
+func (s Secret[T]) String() string {
+	return mask
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (s Secret[T]) GoString() string {
+	return mask
+}
+
+// Format implements the fmt.Formatter interface, so every verb renders the mask.
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	_, _ = f.Write([]byte(mask))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(mask), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + mask + `"`), nil
+}
+
+// Redacted is a string that is never logged, it renders as *** in every formatter and through the fmt package.
+type Redacted string
+
+// String implements the fmt.Stringer interface.
+func (r Redacted) String() string {
+	return mask
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (r Redacted) GoString() string {
+	return mask
+}
+
+// Format implements the fmt.Formatter interface, so every verb renders the mask.
+func (r Redacted) Format(f fmt.State, verb rune) {
+	_, _ = f.Write([]byte(mask))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (r Redacted) MarshalText() ([]byte, error) {
+	return []byte(mask), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r Redacted) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + mask + `"`), nil
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testAudit struct {
+	CreatedBy string
+}
+
+type testAddress struct {
+	City    string `log:"city"`
+	Country string `log:"country"`
+}
+
+type testMandate struct {
+	testAudit
+	ID        int            `log:"id"`
+	Status    string         `log:"status,omitempty"`
+	IBAN      string         `log:"iban,redact"`
+	Debtor    *testAddress   `log:"debtor"`
+	SignedAt  time.Time      `log:"signed_at"`
+	Internal  string         `log:"-"`
+	APIKey    Secret[string] `log:"api_key"`
+	Reference string
+	notLogged string
+}
+
+func TestStruct(t *testing.T) {
+	m := &testMandate{
+		testAudit: testAudit{CreatedBy: "john"},
+		ID:        42,
+		IBAN:      "BE68539007547034",
+		Debtor:    &testAddress{City: "Ghent", Country: "BE"},
+		SignedAt:  time.Unix(0, 0).UTC(),
+		Internal:  "internal",
+		APIKey:    NewSecret("sk_live_123"),
+		Reference: "MNDT-42",
+		notLogged: "hidden",
+	}
+
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: &TextFormatter{MessageField: "msg"}})
+	log.With(Struct("mandate", m)...).Info("signed")
+
+	want := "msg=signed mandate.created_by=john mandate.id=42 mandate.iban=*** mandate.debtor.city=Ghent mandate.debtor.country=BE " +
+		"mandate.signed_at=1970-01-01T00:00:00Z mandate.api_key=*** mandate.reference=MNDT-42\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+}
+
+func TestStruct_nil(t *testing.T) {
+	var m *testMandate
+	fields := Struct("mandate", m)
+	if len(fields) != 1 || fields[0].Value != nil {
+		t.Errorf("\nWant: %v\nGot: %v", []Field{F("mandate", nil)}, fields)
+	}
+
+	fields = Struct("", testMandate{})
+	for _, f := range fields {
+		if f.Key == "debtor" || f.Key == "status" {
+			t.Errorf("unexpected field %s", f.Key)
+		}
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, want := range map[string]string{"ID": "id", "CustomerID": "customer_id", "HTTPStatus": "http_status", "createdAt": "created_at"} {
+		if got := snakeCase(name); got != want {
+			t.Errorf("\nWant: %s\nGot: %s", want, got)
+		}
+	}
+}
+
+func TestSecret(t *testing.T) {
+	s := NewSecret("sk_live_123")
+	r := Redacted("hunter2")
+
+	for _, got := range []string{fmt.Sprintf("%v %+v %#v %s %q %x", s, s, s, s, s, s), fmt.Sprintf("%v %s %d", r, r, r), fmt.Sprint(&s)} {
+		for i := 0; i < len(got); i++ {
+			if c := got[i]; c != '*' && c != ' ' {
+				t.Errorf("secret value leaked: %s", got)
+				break
+			}
+		}
+	}
+	if s.Value() != "sk_live_123" {
+		t.Errorf("\nWant: %s\nGot: %s", "sk_live_123", s.Value())
+	}
+
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter()})
+	log.With(F("password", r)).Infof("connecting with %v", s)
+
+	want := "info - connecting with *** password=***\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+}
+
+func BenchmarkStruct(b *testing.B) {
+	m := &testMandate{ID: 42, Debtor: &testAddress{City: "Ghent"}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Struct("mandate", m)
+	}
+}
@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxTemplates limits the number of parsed templates that are cached, so messages that are built at runtime
+// can not grow the cache without bound.
+const maxTemplates = 1024
+
+var (
+	templates     sync.Map // map[string]*messageTemplate
+	templateCount atomic.Int64
+)
+
+// messageTemplate is a parsed message template such as "Mandate {MandateID} signed by {Customer}".
+type messageTemplate struct {
+	parts []templatePart
+	holes int
+}
+
+// templatePart is either a literal text or a named hole. The text of a hole is its original notation,
+// which is rendered when no argument is given for the hole.
+type templatePart struct {
+	text        string
+	name        string
+	destructure bool
+}
+
+// parseTemplate returns the parsed template, which is cached after the first parse.
+func parseTemplate(s string) *messageTemplate {
+	if t, ok := templates.Load(s); ok {
+		return t.(*messageTemplate)
+	}
+	t := newTemplate(s)
+	if templateCount.Load() < maxTemplates {
+		if _, loaded := templates.LoadOrStore(s, t); !loaded {
+			templateCount.Add(1)
+		}
+	}
+	return t
+}
+
+// newTemplate parses the holes of a template. A hole is a name of letters, digits and underscores between braces,
+// optionally prefixed by @ to log the fields of a struct and followed by a colon and a format which is ignored.
+// Double braces are written as a single brace and anything that is not a valid hole is written as is.
+func newTemplate(s string) *messageTemplate {
+	t := &messageTemplate{}
+	var literal strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c == '{' || c == '}') && i+1 < len(s) && s[i+1] == c {
+			literal.WriteByte(c) // escaped brace
+			i++
+			continue
+		}
+		if c != '{' {
+			literal.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			literal.WriteString(s[i:])
+			break
+		}
+		part, ok := parseHole(s[i : i+end+1])
+		if !ok {
+			literal.WriteByte(c)
+			continue
+		}
+		if literal.Len() > 0 {
+			t.parts = append(t.parts, templatePart{text: literal.String()})
+			literal.Reset()
+		}
+		t.parts = append(t.parts, part)
+		t.holes++
+		i += end
+	}
+	if literal.Len() > 0 {
+		t.parts = append(t.parts, templatePart{text: literal.String()})
+	}
+	return t
+}
+
+// parseHole parses a hole including its braces.
+func parseHole(hole string) (templatePart, bool) {
+	part := templatePart{text: hole}
+	name := hole[1 : len(hole)-1]
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		name = name[:i] // the format is ignored
+	}
+	if strings.HasPrefix(name, "@") {
+		part.destructure = true
+		name = name[1:]
+	}
+	if name == "" {
+		return part, false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return part, false
+		}
+	}
+	part.name = name
+	return part, true
+}
+
+// render appends the template with its holes replaced by the arguments in order. The arguments are appended
+// to fields named after their hole when fields is not nil, arguments without a hole are named by their position.
+func (t *messageTemplate) render(dst []byte, args []interface{}, fields *[]Field) []byte {
+	n := 0
+	for _, p := range t.parts {
+		if p.name == "" || n >= len(args) {
+			dst = append(dst, p.text...)
+			continue
+		}
+		v := resolve(args[n])
+		n++
+		if p.destructure {
+			fs := Struct(p.name, v)
+			dst = appendStruct(dst, p.name, fs)
+			if fields != nil {
+				*fields = append(*fields, fs...)
+			}
+			continue
+		}
+		dst = appendValue(dst, v)
+		if fields != nil {
+			*fields = append(*fields, F(p.name, v))
+		}
+	}
+	if fields != nil {
+		for ; n < len(args); n++ {
+			*fields = append(*fields, F("arg"+strconv.Itoa(n), resolve(args[n])))
+		}
+	}
+	return dst
+}
+
+// appendStruct appends the fields of a destructured hole as {key=value}, so redacted fields are rendered masked.
+func appendStruct(dst []byte, name string, fields []Field) []byte {
+	if len(fields) == 1 && fields[0].Key == name {
+		return appendValue(dst, fields[0].Value) // not a struct
+	}
+	enc := compactEncoder{buf: append(dst, '{'), first: true}
+	for _, f := range fields {
+		enc.AddValue(strings.TrimPrefix(f.Key, name+"."), f.Value)
+	}
+	return append(enc.buf, '}')
+}
+
+// captures reports whether the field was captured by a hole that is not destructured.
+func (t *messageTemplate) captures(key string) bool {
+	for _, p := range t.parts {
+		if p.name != "" && !p.destructure && p.name == key {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTemplate renders the message of the event as a template and captures the template arguments as fields.
+// The template is kept on the event when it contains holes.
+func applyTemplate(e *Event) {
+	t := parseTemplate(e.Message)
+	if t.holes > 0 {
+		e.Template = e.Message
+	}
+	e.buf = t.render(e.buf[:0], e.args, &e.Fields)
+	e.Message = string(e.buf)
+	e.buf = e.buf[:0]
+	e.args = nil
+}
+
+// renderTemplate returns the template with its holes replaced by the arguments.
+func renderTemplate(template string, args []interface{}) string {
+	return string(parseTemplate(template).render(nil, args, nil))
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		args     []interface{}
+		want     string
+	}{
+		{"Mandate {MandateID} signed by {Customer}", []interface{}{42, "John"}, "Mandate 42 signed by John"},
+		{"{A}{B}", []interface{}{1, 2}, "12"},
+		{"Took {Elapsed:0.00}", []interface{}{time.Second}, "Took 1s"},
+		{"Missing {First} and {Second}", []interface{}{1}, "Missing 1 and {Second}"},
+		{"Escaped {{Name}} and }}", []interface{}{1}, "Escaped {Name} and }"},
+		{"Not a hole { Name } {} {@} {a-b}", []interface{}{1}, "Not a hole { Name } {} {@} {a-b}"},
+		{"Unclosed {Name", []interface{}{1}, "Unclosed {Name"},
+		{"Nested {{Name} {Value}", []interface{}{1}, "Nested {Name} 1"},
+		{"Lazy {Value}", []interface{}{func() string { return "resolved" }}, "Lazy resolved"},
+	}
+	for _, tt := range tests {
+		if got := renderTemplate(tt.template, tt.args); got != tt.want {
+			t.Errorf("\nWant: %s\nGot: %s", tt.want, got)
+		}
+	}
+}
+
+func TestParseTemplate_cached(t *testing.T) {
+	template := "Mandate {MandateID} cached"
+	if parseTemplate(template) != parseTemplate(template) {
+		t.Errorf("template %q was parsed twice", template)
+	}
+}
+
+func TestLogger_template(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: &TextFormatter{MessageField: "msg", TemplateField: "template"}})
+
+	log.With(F("app", "core")).Info("Mandate {MandateID} signed by {Customer}", 42, "John", "extra")
+	want := "msg=\"Mandate 42 signed by John\" template=\"Mandate {MandateID} signed by {Customer}\" app=core MandateID=42 Customer=John arg2=extra\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+
+	buf.Reset()
+	log.Info("Mandate {MandateID} {{pending}}")
+	want = "msg=\"Mandate {MandateID} {{pending}}\"\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+}
+
+func TestLogger_templateDestructure(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: &TextFormatter{MessageField: "msg"}})
+
+	log.Info("Debtor moved to {@Debtor}", testAddress{City: "Ghent", Country: "BE"})
+	want := "msg=\"Debtor moved to {city=Ghent country=BE}\" Debtor.city=Ghent Debtor.country=BE\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+}
+
+func TestLogger_templateRedacted(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: &TextFormatter{MessageField: "msg"}})
+
+	log.Info("Signed {@Mandate}", testMandate{ID: 42, IBAN: "BE68539007547034"})
+	if got := buf.String(); strings.Contains(got, "BE68539007547034") || !strings.Contains(got, "iban=***") {
+		t.Errorf("expected the iban to be redacted, got: %s", got)
+	}
+}
+
+func TestLogger_templateRecorded(t *testing.T) {
+	var buf bytes.Buffer
+	calls := 0
+	recorder := NewRecorder(10, 0)
+	log := NewWithOptions(Options{
+		Writer:    &buf,
+		Formatter: &TextFormatter{MessageField: "msg", TemplateField: "template"},
+		Level:     LevelInfo,
+		Recorder:  recorder,
+	})
+
+	log.Debug("Exporting {Batch}", testBatch{calls: &calls})
+	if calls != 0 {
+		t.Errorf("\nWant: %d\nGot: %d", 0, calls)
+	}
+
+	recorder.Dump()
+	want := "msg=\"Exporting <pain.008/>\" template=\"Exporting {Batch}\" Batch=<pain.008/>\n"
+	if buf.String() != want || calls != 1 {
+		t.Errorf("\nWant: %s (1 call)\nGot: %s (%d calls)", want, buf.String(), calls)
+	}
+}
+
+func TestJSONFormatter_template(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{Writer: &buf, Formatter: &JSONFormatter{MessageField: "msg", TemplateField: "template"}})
+
+	log.Info("Mandate {MandateID} signed", 42)
+	want := `{"msg":"Mandate 42 signed","template":"Mandate {MandateID} signed","MandateID":42}` + "\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+}
+
+func TestPrettyFormatter_template(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{
+		Writer:    &buf,
+		Formatter: &PrettyFormatter{TimeFormat: "15:04"},
+		Clock:     NewFixedClock(DeterministicTime),
+	})
+
+	log.With(F("app", "core")).Info("Mandate {MandateID} signed", 42)
+	want := "\x1b[90m00:00\x1b[0m \x1b[32mINF\x1b[0m \x1b[90m[\x1b[0m\x1b[37mdefault\x1b[0m\x1b[90m]\x1b[0m Mandate 42 signed \x1b[36mapp=\x1b[0mcore\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %q\nGot: %q", want, buf.String())
+	}
+}
+
+func TestCountSampler_template(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithOptions(Options{
+		Writer:    &buf,
+		Formatter: &TextFormatter{MessageField: "msg"},
+		Sampler:   NewCountSampler(time.Minute, 1, 0),
+	})
+
+	log.Info("Mandate {MandateID} signed", 1)
+	log.Info("Mandate {MandateID} signed", 2)
+	want := "msg=\"Mandate 1 signed\" MandateID=1\n"
+	if buf.String() != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, buf.String())
+	}
+}
+
+func TestLogger_templatePanic(t *testing.T) {
+	log := NewWithOptions(Options{Writer: io.Discard})
+	defer func() {
+		want := "Mandate 42 revoked"
+		if r := recover(); r != want {
+			t.Errorf("\nWant: %s\nGot: %v", want, r)
+		}
+	}()
+	log.Panic("Mandate {MandateID} revoked", 42)
+}
+
+func BenchmarkTemplate(b *testing.B) {
+	logger := New(io.Discard)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("Mandate {MandateID} signed by {Customer}", 42, "John")
+		}
+	})
+}
@@ -0,0 +1,258 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Field names used when enriching events with the span carried by the context of the logger.
+const (
+	TraceIDField    = "trace_id"
+	SpanIDField     = "span_id"
+	TraceFlagsField = "trace_flags"
+)
+
+const (
+	// traceParentVersion is the only version of the traceparent header defined by the W3C specification.
+	traceParentVersion = "00"
+
+	// traceParentLength is the length of a version 00 traceparent header.
+	traceParentLength = 55
+
+	// maxTraceStateMembers is the maximum number of list members allowed in the tracestate header.
+	maxTraceStateMembers = 32
+
+	// FlagSampled is the trace flag that indicates the caller may have recorded the trace.
+	FlagSampled TraceFlags = 0x01
+)
+
+// ErrInvalidTraceParent is returned when a traceparent header cannot be parsed.
+var ErrInvalidTraceParent = errors.New("logger: invalid traceparent")
+
+// ErrInvalidTraceState is returned when a tracestate header cannot be parsed.
+var ErrInvalidTraceState = errors.New("logger: invalid tracestate")
+
+// TraceID identifies a trace, as defined by the W3C trace context specification.
+type TraceID [16]byte
+
+// String returns the lowercase hex encoding of the trace ID.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// IsValid returns true when the trace ID is not all zeros.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// String returns the lowercase hex encoding of the span ID.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsValid returns true when the span ID is not all zeros.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// TraceFlags are the flags of a traceparent header.
+type TraceFlags byte
+
+// String returns the two character hex encoding of the flags.
+func (f TraceFlags) String() string {
+	return hex.EncodeToString([]byte{byte(f)})
+}
+
+// TraceStateMember is a single key value pair of the tracestate header.
+type TraceStateMember struct {
+	Key   string
+	Value string
+}
+
+// TraceState holds the vendor specific key value pairs of the tracestate header.
+type TraceState []TraceStateMember
+
+// ParseTraceState parses the tracestate header. Empty list members are ignored.
+func ParseTraceState(header string) (TraceState, error) {
+	var state TraceState
+	for _, member := range strings.Split(header, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(member, "=")
+		if !ok || !validTraceStateKey(key) || !validTraceStateValue(value) {
+			return nil, ErrInvalidTraceState
+		}
+		state = append(state, TraceStateMember{Key: key, Value: value})
+	}
+	if len(state) > maxTraceStateMembers {
+		return nil, ErrInvalidTraceState
+	}
+	return state, nil
+}
+
+// String returns the tracestate header.
+func (ts TraceState) String() string {
+	var b strings.Builder
+	for i, m := range ts {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(m.Key)
+		b.WriteByte('=')
+		b.WriteString(m.Value)
+	}
+	return b.String()
+}
+
+// validTraceStateKey checks the key against the simple and multi-tenant key formats.
+func validTraceStateKey(key string) bool {
+	if key == "" || len(key) > 256 {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '*' || c == '/' || c == '@') {
+			return false
+		}
+	}
+	return true
+}
+
+// validTraceStateValue checks the value only contains printable ASCII except for comma and equals.
+func validTraceStateValue(value string) bool {
+	if value == "" || len(value) > 256 || value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c < ' ' || c > '~' || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+// SpanContext identifies a span and the trace it belongs to, as propagated by the traceparent
+// and tracestate headers.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Flags   TraceFlags
+	State   TraceState
+}
+
+// NewSpanContext creates a span context for a new trace with random identifiers.
+func NewSpanContext(flags TraceFlags) SpanContext {
+	sc := SpanContext{SpanID: NewSpanID(), Flags: flags}
+	for !sc.TraceID.IsValid() {
+		_, _ = rand.Read(sc.TraceID[:])
+	}
+	return sc
+}
+
+// NewSpanID returns a random span ID.
+func NewSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// Child returns a span context for a new span within the same trace.
+func (sc SpanContext) Child() SpanContext {
+	sc.SpanID = NewSpanID()
+	return sc
+}
+
+// IsValid returns true when both the trace ID and span ID are valid.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// IsSampled returns true when the sampled flag is set.
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&FlagSampled != 0
+}
+
+// TraceParent returns the version 00 traceparent header of the span context.
+func (sc SpanContext) TraceParent() string {
+	b := make([]byte, 0, traceParentLength)
+	b = append(b, traceParentVersion...)
+	b = append(b, '-')
+	b = hex.AppendEncode(b, sc.TraceID[:])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, sc.SpanID[:])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, []byte{byte(sc.Flags)})
+	return string(b)
+}
+
+// ParseTraceParent parses the traceparent header. Headers of future versions are accepted
+// as long as they start with the fields of version 00.
+func ParseTraceParent(header string) (SpanContext, error) {
+	var sc SpanContext
+	header = strings.TrimSpace(header)
+	if len(header) < traceParentLength || header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return sc, ErrInvalidTraceParent
+	}
+
+	version, ok := decodeLowerHex(header[0:2])
+	if !ok || version[0] == 0xff || (version[0] == 0 && len(header) != traceParentLength) {
+		return sc, ErrInvalidTraceParent
+	}
+	if len(header) > traceParentLength && header[traceParentLength] != '-' {
+		return sc, ErrInvalidTraceParent
+	}
+
+	traceID, ok := decodeLowerHex(header[3:35])
+	if !ok {
+		return sc, ErrInvalidTraceParent
+	}
+	spanID, ok := decodeLowerHex(header[36:52])
+	if !ok {
+		return sc, ErrInvalidTraceParent
+	}
+	flags, ok := decodeLowerHex(header[53:55])
+	if !ok {
+		return sc, ErrInvalidTraceParent
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Flags = TraceFlags(flags[0])
+	if !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+	return sc, nil
+}
+
+// decodeLowerHex decodes a hex string that only consists of lowercase characters.
+func decodeLowerHex(s string) ([]byte, bool) {
+	if strings.ToLower(s) != s {
+		return nil, false
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+// spanKey is the key used to store the span context in a context.
+type spanKey struct{}
+
+// ContextWithSpan returns a copy of the context that carries the span context.
+func ContextWithSpan(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanKey{}, sc)
+}
+
+// SpanFromContext returns the span context carried by the context.
+func SpanFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	var tests = []struct {
+		header string
+		valid  bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		sc, err := ParseTraceParent(test.header)
+		if test.valid != (err == nil) {
+			t.Errorf("unexpected result for %q: %v", test.header, err)
+		}
+		if err == nil && sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("unexpected trace id %s for %q", sc.TraceID, test.header)
+		}
+	}
+}
+
+func TestSpanContext_TraceParent(t *testing.T) {
+	header := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := ParseTraceParent(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sc.TraceParent(); got != header {
+		t.Errorf("\nWant: %s\nGot: %s", header, got)
+	}
+	if !sc.IsSampled() {
+		t.Errorf("expected span to be sampled")
+	}
+
+	child := sc.Child()
+	if child.TraceID != sc.TraceID || child.SpanID == sc.SpanID {
+		t.Errorf("expected child span in the same trace")
+	}
+
+	generated := NewSpanContext(FlagSampled)
+	if _, err := ParseTraceParent(generated.TraceParent()); err != nil {
+		t.Errorf("expected generated traceparent to be valid: %v", err)
+	}
+}
+
+func TestParseTraceState(t *testing.T) {
+	state, err := ParseTraceState("rojo=00f067aa0ba902b7, congo=t61rcWkgMzE,,tenant@vendor=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.String(); got != "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE,tenant@vendor=x" {
+		t.Errorf("unexpected tracestate: %s", got)
+	}
+
+	for _, header := range []string{"Rojo=1", "rojo", "rojo=a,b", "rojo=a=b"} {
+		if _, err := ParseTraceState(header); err == nil {
+			t.Errorf("expected %q to be invalid", header)
+		}
+	}
+}
+
+func TestLoggerWithContext(t *testing.T) {
+	var buf bytes.Buffer
+	sc, _ := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := ContextWithSpan(context.Background(), sc)
+
+	log := New(&buf).WithContext(ctx)
+	log.Info("traced")
+
+	want := "trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 trace_flags=01\n"
+	if got := buf.String(); !strings.HasSuffix(got, want) {
+		t.Errorf("expected trace fields, got: %s", got)
+	}
+
+	buf.Reset()
+	New(&buf).WithContext(context.Background()).Info("untraced")
+	if strings.Contains(buf.String(), "trace_id") {
+		t.Errorf("expected no trace fields without span, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// defaultMaxLineLength is the maximum length of a line written through a LineWriter before it is split.
+const defaultMaxLineLength = 8 << 10
+
+// PartialField is the field name that marks the parts of a line that was split because it was too long.
+const PartialField = "partial"
+
+// LineWriter is an io.Writer that splits the written output into lines and logs each line as an event.
+// Invalid UTF-8 and control characters are escaped, and lines that are too long are split into parts.
+// Call Flush to log the remaining partial line once no more output is expected.
+type LineWriter struct {
+	mu          sync.Mutex
+	l           *Logger
+	lvl         Level
+	max         int
+	parseLevels bool
+	buf         []byte
+}
+
+// NewLineWriter creates a writer that logs every line at the given level. Lines longer than maxLineLength
+// are split into parts that are logged with the partial field, zero uses a maximum of 8KiB.
+func NewLineWriter(l *Logger, lvl Level, maxLineLength int) *LineWriter {
+	if maxLineLength <= 0 {
+		maxLineLength = defaultMaxLineLength
+	}
+	return &LineWriter{l: l, lvl: lvl, max: maxLineLength}
+}
+
+// Writer returns an io.Writer that logs every written line at the given level.
+func (l *Logger) Writer(lvl Level) *LineWriter {
+	return NewLineWriter(l, lvl, 0)
+}
+
+// StdLogger returns a standard library logger that writes every line through the logger, for APIs such as
+// http.Server.ErrorLog. Lines starting with a known level prefix such as "ERROR:" are logged at that level,
+// all other lines at the given level.
+func (l *Logger) StdLogger(lvl Level) *log.Logger {
+	w := NewLineWriter(l, lvl, 0)
+	w.parseLevels = true
+	return log.New(w, "", 0)
+}
+
+// RedirectStdLog redirects the output of the standard library log package through the logger, see StdLogger.
+// The returned function restores the previous output, flags and prefix.
+func RedirectStdLog(l *Logger, lvl Level) (restore func()) {
+	w := NewLineWriter(l, lvl, 0)
+	w.parseLevels = true
+
+	output, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(w)
+	log.SetFlags(0)
+	log.SetPrefix("")
+	return func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}
+
+// Write implements the io.Writer interface.
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		if i := bytes.IndexByte(w.buf, newline); i >= 0 && i <= w.max {
+			w.log(w.buf[:i], false)
+			w.buf = w.buf[i+1:]
+			continue
+		}
+		if len(w.buf) > w.max {
+			// split long line at a rune boundary
+			n := w.max
+			for n > 0 && !utf8.RuneStart(w.buf[n]) {
+				n--
+			}
+			if n == 0 {
+				n = w.max
+			}
+			w.log(w.buf[:n], true)
+			w.buf = w.buf[n:]
+			continue
+		}
+		break
+	}
+
+	if len(w.buf) == 0 {
+		w.buf = w.buf[:0:0] // reclaim memory of consumed lines
+	}
+	return len(p), nil
+}
+
+// Flush logs the remaining partial line.
+func (w *LineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.log(w.buf, false)
+		w.buf = nil
+	}
+}
+
+// Close flushes the remaining partial line, which allows the writer to be used as an io.WriteCloser.
+func (w *LineWriter) Close() error {
+	w.Flush()
+	return nil
+}
+
+var _ io.WriteCloser = (*LineWriter)(nil)
+
+func (w *LineWriter) log(line []byte, partial bool) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	message := escapeLine(line)
+
+	lvl := w.lvl
+	if w.parseLevels {
+		if parsed, rest, ok := parseLevelPrefix(message); ok {
+			lvl, message = parsed, rest
+		}
+	}
+
+	if partial {
+		w.l.With(F(PartialField, true)).log(lvl, message, nil)
+	} else {
+		w.l.log(lvl, message, nil)
+	}
+}
+
+// levelPrefixes maps the prefixes commonly used by libraries that log through the standard library to levels.
+var levelPrefixes = map[string]Level{
+	"FATAL":   LevelFatal,
+	"PANIC":   LevelFatal,
+	"ERROR":   LevelError,
+	"ERR":     LevelError,
+	"WARN":    LevelWarning,
+	"WARNING": LevelWarning,
+	"INFO":    LevelInfo,
+	"NOTICE":  LevelInfo,
+	"DEBUG":   LevelDebug,
+	"TRACE":   LevelTrace,
+}
+
+// parseLevelPrefix parses prefixes such as "ERROR:" or "[warn]" and returns the level and the remaining line.
+// A level without a colon or brackets is part of the message, as in "error reading request body".
+func parseLevelPrefix(line string) (Level, string, bool) {
+	rest := line
+	bracket := strings.HasPrefix(rest, "[")
+	if bracket {
+		rest = rest[1:]
+	}
+
+	end := strings.IndexAny(rest, ":]")
+	if end <= 0 {
+		return 0, line, false
+	}
+	lvl, ok := levelPrefixes[strings.ToUpper(rest[:end])]
+	if !ok || bracket != (rest[end] == ']') {
+		return 0, line, false
+	}
+
+	rest = rest[end+1:]
+	if bracket {
+		rest = strings.TrimPrefix(rest, ":")
+	}
+	return lvl, strings.TrimLeft(rest, " "), true
+}
+
+// escapeLine converts the output into valid UTF-8 and escapes control characters other than tab.
+func escapeLine(line []byte) string {
+	clean := utf8.Valid(line)
+	for _, b := range line {
+		if (b < ' ' && b != '\t') || b == 0x7f {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return string(line)
+	}
+
+	var b strings.Builder
+	for len(line) > 0 {
+		r, size := utf8.DecodeRune(line)
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteString(`\x`)
+			b.WriteByte(hexDigits[line[0]>>4])
+			b.WriteByte(hexDigits[line[0]&0xf])
+		case (r < ' ' && r != '\t') || r == 0x7f:
+			b.WriteString(`\x`)
+			b.WriteByte(hexDigits[r>>4])
+			b.WriteByte(hexDigits[r&0xf])
+		default:
+			b.WriteRune(r)
+		}
+		line = line[size:]
+	}
+	return b.String()
+}
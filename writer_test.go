@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter()})
+	w := NewLineWriter(l, LevelInfo, 8)
+
+	_, _ = w.Write([]byte("hel"))
+	_, _ = w.Write([]byte("lo\r\nthis line is too long\nbin\x00\xff\n"))
+	_, _ = w.Write([]byte("partial"))
+	w.Flush()
+
+	want := "info - hello\n" +
+		"info - this lin partial=true\n" +
+		"info - e is too partial=true\n" +
+		"info -  long\n" +
+		"info - bin\\x00\\xff\n" +
+		"info - partial\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestParseLevelPrefix(t *testing.T) {
+	var tests = []struct {
+		line    string
+		level   Level
+		message string
+		ok      bool
+	}{
+		{"ERROR: connection refused", LevelError, "connection refused", true},
+		{"[warn] disk almost full", LevelWarning, "disk almost full", true},
+		{"[DEBUG]: cache miss", LevelDebug, "cache miss", true},
+		{"http: TLS handshake error", 0, "http: TLS handshake error", false},
+		{"[error connection refused", 0, "[error connection refused", false},
+		{"errors: none", 0, "errors: none", false},
+		{"error reading request body", 0, "error reading request body", false},
+	}
+
+	for _, test := range tests {
+		level, message, ok := parseLevelPrefix(test.line)
+		if level != test.level || message != test.message || ok != test.ok {
+			t.Errorf("unexpected result for %q: %s, %q, %t", test.line, level, message, ok)
+		}
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter()})
+
+	std := l.StdLogger(LevelWarning)
+	std.Print("http: TLS handshake error")
+	std.Printf("ERROR: %s", "connection refused")
+	std.Print("error reading request body")
+
+	want := "warn - http: TLS handshake error\n" +
+		"error - connection refused\n" +
+		"warn - error reading request body\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithOptions(Options{Writer: &buf, Formatter: NewJournalFormatter()})
+
+	restore := RedirectStdLog(l, LevelInfo)
+	log.Println("hello from the standard library")
+	log.Println("[error] something failed")
+	restore()
+
+	want := "info - hello from the standard library\n" +
+		"error - something failed\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nWant: %s\nGot: %s", want, got)
+	}
+}